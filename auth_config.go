@@ -1,10 +1,15 @@
 package auth
 
 type AuthConfig struct {
-	Secret            string       `yaml:"secret" mapstructure:"secret" json:"secret,omitempty" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
-	Expires           int64        `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
-	MaxPasswordFailed int          `yaml:"max_password_failed" mapstructure:"max_password_failed" json:"maxPasswordFailed,omitempty" gorm:"column:maxpasswordfailed" bson:"maxPasswordFailed,omitempty" dynamodbav:"maxPasswordFailed,omitempty" firestore:"maxPasswordFailed,omitempty"`
-	LockedMinutes     int          `yaml:"locked_minutes" mapstructure:"locked_minutes" json:"lockedMinutes,omitempty" gorm:"column:lockedminutes" bson:"lockedMinutes,omitempty" dynamodbav:"lockedMinutes,omitempty" firestore:"lockedMinutes,omitempty"`
-	MaxPasswordAge    int32        `yaml:"max_password_age" mapstructure:"max_password_age" json:"maxPasswordAge,omitempty" gorm:"column:maxpasswordage" bson:"maxPasswordAge,omitempty" dynamodbav:"maxPasswordAge,omitempty" firestore:"maxPasswordAge,omitempty"`
-	Schema            SchemaConfig `yaml:"schema" mapstructure:"schema" json:"schema,omitempty" gorm:"column:schema" bson:"schema,omitempty" dynamodbav:"schema,omitempty" firestore:"schema,omitempty"`
+	Secret            string                      `yaml:"secret" mapstructure:"secret" json:"secret,omitempty" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
+	Expires           int64                       `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
+	MaxPasswordFailed int                         `yaml:"max_password_failed" mapstructure:"max_password_failed" json:"maxPasswordFailed,omitempty" gorm:"column:maxpasswordfailed" bson:"maxPasswordFailed,omitempty" dynamodbav:"maxPasswordFailed,omitempty" firestore:"maxPasswordFailed,omitempty"`
+	LockedMinutes     int                         `yaml:"locked_minutes" mapstructure:"locked_minutes" json:"lockedMinutes,omitempty" gorm:"column:lockedminutes" bson:"lockedMinutes,omitempty" dynamodbav:"lockedMinutes,omitempty" firestore:"lockedMinutes,omitempty"`
+	MaxPasswordAge    int32                       `yaml:"max_password_age" mapstructure:"max_password_age" json:"maxPasswordAge,omitempty" gorm:"column:maxpasswordage" bson:"maxPasswordAge,omitempty" dynamodbav:"maxPasswordAge,omitempty" firestore:"maxPasswordAge,omitempty"`
+	Lockout           LockoutConfig               `yaml:"lockout" mapstructure:"lockout" json:"lockout,omitempty" gorm:"column:lockout" bson:"lockout,omitempty" dynamodbav:"lockout,omitempty" firestore:"lockout,omitempty"`
+	RateLimit         RateLimitConfig             `yaml:"rate_limit" mapstructure:"rate_limit" json:"rateLimit,omitempty" gorm:"column:ratelimit" bson:"rateLimit,omitempty" dynamodbav:"rateLimit,omitempty" firestore:"rateLimit,omitempty"`
+	Policy            PasswordPolicyConfig        `yaml:"policy" mapstructure:"policy" json:"policy,omitempty" gorm:"column:policy" bson:"policy,omitempty" dynamodbav:"policy,omitempty" firestore:"policy,omitempty"`
+	Schema            SchemaConfig                `yaml:"schema" mapstructure:"schema" json:"schema,omitempty" gorm:"column:schema" bson:"schema,omitempty" dynamodbav:"schema,omitempty" firestore:"schema,omitempty"`
+	Tenant            TenantConfig                `yaml:"tenant" mapstructure:"tenant" json:"tenant,omitempty" gorm:"column:tenant" bson:"tenant,omitempty" dynamodbav:"tenant,omitempty" firestore:"tenant,omitempty"`
+	Tenants           map[string]TenantAuthConfig `yaml:"tenants" mapstructure:"tenants" json:"tenants,omitempty" gorm:"column:tenants" bson:"tenants,omitempty" dynamodbav:"tenants,omitempty" firestore:"tenants,omitempty"`
 }
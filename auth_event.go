@@ -0,0 +1,30 @@
+package auth
+
+import "time"
+
+const (
+	EventLoginSuccess      = "login_success"
+	EventLoginFailure      = "login_failure"
+	EventLockout           = "lockout"
+	EventUnlock            = "unlock"
+	EventTwoFactorSent     = "two_factor_sent"
+	EventTwoFactorVerified = "two_factor_verified"
+	EventRateLimited       = "rate_limited"
+	EventLogout            = "logout"
+	EventTokenRevoked      = "token_revoked"
+	EventOAuth2Linked      = "oauth2_linked"
+	EventPasswordReset     = "password_reset"
+	EventPasswordChanged   = "password_changed"
+)
+
+type AuthEvent struct {
+	Type     string    `yaml:"type" mapstructure:"type" json:"type,omitempty" gorm:"column:type" bson:"type,omitempty" dynamodbav:"type,omitempty" firestore:"type,omitempty"`
+	UserId   string    `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:userid" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Username string    `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Ip       string    `yaml:"ip" mapstructure:"ip" json:"ip,omitempty" gorm:"column:ip" bson:"ip,omitempty" dynamodbav:"ip,omitempty" firestore:"ip,omitempty"`
+	Device   string    `yaml:"device" mapstructure:"device" json:"device,omitempty" gorm:"column:device" bson:"device,omitempty" dynamodbav:"device,omitempty" firestore:"device,omitempty"`
+	Provider string    `yaml:"provider" mapstructure:"provider" json:"provider,omitempty" gorm:"column:provider" bson:"provider,omitempty" dynamodbav:"provider,omitempty" firestore:"provider,omitempty"`
+	Status   int       `yaml:"status" mapstructure:"status" json:"status" gorm:"column:status" bson:"status" dynamodbav:"status" firestore:"status"`
+	Reason   string    `yaml:"reason" mapstructure:"reason" json:"reason,omitempty" gorm:"column:reason" bson:"reason,omitempty" dynamodbav:"reason,omitempty" firestore:"reason,omitempty"`
+	Time     time.Time `yaml:"time" mapstructure:"time" json:"time,omitempty" gorm:"column:time" bson:"time,omitempty" dynamodbav:"time,omitempty" firestore:"time,omitempty"`
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type AuthEventSink interface {
+	Write(ctx context.Context, event AuthEvent) error
+}
+
+type MultiEventSink struct {
+	Sinks []AuthEventSink
+}
+
+func NewMultiEventSink(sinks ...AuthEventSink) *MultiEventSink {
+	return &MultiEventSink{Sinks: sinks}
+}
+
+func (s *MultiEventSink) Write(ctx context.Context, event AuthEvent) error {
+	var err error
+	for _, sink := range s.Sinks {
+		if er1 := sink.Write(ctx, event); er1 != nil && err == nil {
+			err = er1
+		}
+	}
+	return err
+}
+
+func WriteEvent(ctx context.Context, sink AuthEventSink, event AuthEvent) {
+	if sink == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if err := sink.Write(ctx, event); err != nil {
+		log.Println(err)
+	}
+}
@@ -8,6 +8,11 @@ import (
 	"time"
 )
 
+const verifiedPrefix = "verified:"
+
+// sentPrefix marks when the last code was sent; it outlives the code itself, which a failed or exhausted compare deletes.
+const sentPrefix = "sent:"
+
 type Authenticator struct {
 	Status             Status
 	Repository         UserRepository
@@ -18,8 +23,17 @@ type Authenticator struct {
 	MaxPasswordFailed  int
 	CodeExpires        int64
 	CodeRepository     CodeRepository
+	MaxCodeAttempts    int
+	CodeResendInterval int64
 	SendCode           func(ctx context.Context, to string, code string, expireAt time.Time, params interface{}) error
+	Delivery           CodeDelivery
 	GenerateCode       func() string
+	TOTP               *TOTPService
+	RecoveryCodes      *RecoveryCodeService
+	Lockout            LockoutPolicy
+	Events             AuthEventSink
+	Ip                 string
+	Tenants            map[string]TenantAuthConfig
 }
 
 func NewBasicAuthenticator(status Status, check func(context.Context, AuthInfo) (AuthResult, error), userInfoService UserRepository, loadPrivileges func(context.Context, string) ([]Privilege, error), options ...int) *Authenticator {
@@ -32,6 +46,9 @@ func NewBasicAuthenticatorWithTwoFactors(status Status, check func(context.Conte
 	if sendCode != nil && (codeService == nil || codeExpires <= 0) {
 		panic(errors.New("when using two-factor, codeService and sendCode must not be nil, and codeExpires must be greater than 0"))
 	}
+	if generate == nil {
+		generate = GenerateCode
+	}
 	lockedMinutes := 0
 	maxPasswordFailed := 0
 	if len(options) > 0 && options[0] > 0 {
@@ -69,8 +86,10 @@ func NewAuthenticatorWithTwoFactors(status Status, userRepository UserRepository
 		panic(errors.New("when using two-factor, codeService and sendCode must not be nil, and codeExpires must be greater than 0"))
 	}
 	var generate func() string
-	if len(options) >= 1 {
+	if len(options) >= 1 && options[0] != nil {
 		generate = options[0]
+	} else {
+		generate = GenerateCode
 	}
 	service := &Authenticator{
 		Status:             status,
@@ -87,6 +106,13 @@ func NewAuthenticatorWithTwoFactors(status Status, userRepository UserRepository
 }
 
 func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthResult, error) {
+	result, err := s.authenticate(ctx, info)
+	if s.Events != nil {
+		s.writeResult(ctx, info, result, err)
+	}
+	return result, err
+}
+func (s *Authenticator) authenticate(ctx context.Context, info AuthInfo) (AuthResult, error) {
 	result := AuthResult{Status: s.Status.Fail}
 
 	username := info.Username
@@ -103,9 +129,12 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 			return result, er0
 		}
 		if s.Repository == nil {
-			account := UserAccount{}
+			// keep the account found by the check, so the handler can issue tokens for it
+			if result.User == nil || len(result.User.Id) == 0 {
+				account := UserAccount{Id: info.Username}
+				result.User = &account
+			}
 			result.Status = s.Status.Success
-			result.User = &account
 			return result, nil
 		}
 	}
@@ -125,18 +154,13 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 			return result, er2
 		}
 		if !validPassword {
-			var lockUntilTime *time.Time
-			if s.LockedMinutes > 0 && s.MaxPasswordFailed > 0 && user.FailCount != nil && *user.FailCount >= s.MaxPasswordFailed {
-				l := time.Now().Add(time.Minute * time.Duration(s.LockedMinutes))
-				lockUntilTime = &l
-			}
-			er3 := s.Repository.Fail(ctx, user.Id, user.FailCount, lockUntilTime)
-			if er3 != nil {
+			if er3 := s.fail(ctx, info, *user); er3 != nil {
 				return result, er3
 			}
 			result.Status = s.Status.WrongPassword
 			return result, nil
 		}
+		s.rehash(ctx, user.Id, password, user.Password)
 		account := UserAccount{}
 		result.User = &account
 	}
@@ -158,6 +182,9 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 
 	var passwordExpiredTime *time.Time = nil // date.addDays(time.Now(), 10)
 	mpa := user.MaxPasswordAge
+	if t, ok := s.Tenants[GetTenant(ctx)]; ok && t.MaxPasswordAge > 0 && mpa == nil {
+		mpa = &t.MaxPasswordAge
+	}
 	if user.PasswordChangedTime != nil && mpa != nil && *mpa != 0 {
 		t := addDays(*user.PasswordChangedTime, *mpa)
 		passwordExpiredTime = &t
@@ -178,7 +205,32 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 
 	if user.TwoFactors {
 		userId := user.Id
+		totp := false
+		if s.TOTP != nil {
+			enrolled, er0 := s.TOTP.Enrolled(ctx, userId)
+			if er0 != nil {
+				return result, er0
+			}
+			totp = enrolled
+		}
 		if info.Step <= 0 {
+			if er0 := s.markVerified(ctx, userId); er0 != nil {
+				return result, er0
+			}
+			if totp {
+				result.Status = s.Status.TwoFactorRequired
+				return result, nil
+			}
+			if s.CodeResendInterval > 0 {
+				throttled, er0 := s.resendThrottled(ctx, userId)
+				if er0 != nil {
+					return result, er0
+				}
+				if throttled {
+					result.Status = s.Status.TooManyRequests
+					return result, nil
+				}
+			}
 			var codeSend string
 			if s.GenerateCode != nil {
 				codeSend = s.GenerateCode()
@@ -193,7 +245,17 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 			expiredAt := addSeconds(time.Now(), s.CodeExpires)
 			count, er1 := s.CodeRepository.Save(ctx, userId, codeSave, expiredAt)
 			if count > 0 && er1 == nil {
-				er3 := s.SendCode(ctx, username, codeSend, expiredAt, user.Contact)
+				if s.CodeResendInterval > 0 {
+					if _, er2 := s.CodeRepository.Save(ctx, sentPrefix+userId, "1", addSeconds(time.Now(), s.CodeResendInterval)); er2 != nil {
+						return result, er2
+					}
+				}
+				var er3 error
+				if s.Delivery != nil {
+					er3 = s.Delivery.Deliver(ctx, info.Sender, *user, codeSend, expiredAt)
+				} else {
+					er3 = s.SendCode(ctx, username, codeSend, expiredAt, user.Contact)
+				}
 				if er3 != nil {
 					return result, er3
 				}
@@ -201,21 +263,35 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 				return result, nil
 			}
 		}
-		code, expiredAt, er4 := s.CodeRepository.Load(ctx, userId)
-		if er4 != nil || len(code) == 0 {
-			return result, er4
-		}
-		if compareDate(expiredAt, time.Now()) < 0 {
-			deleteCode(ctx, s.CodeRepository, userId)
-			return result, nil
+		if info.Step > 0 {
+			verified, er4 := s.isVerified(ctx, userId)
+			if er4 != nil || !verified {
+				return result, er4
+			}
 		}
-		valid, er5 := s.PasswordComparator.Compare(info.Passcode, code)
-		if er5 == nil {
-			deleteCode(ctx, s.CodeRepository, userId)
+		var valid bool
+		var status int
+		var er5 error
+		recovery := s.RecoveryCodes != nil && s.RecoveryCodes.IsRecoveryCode(info.Passcode)
+		if recovery {
+			status = s.Status.PasscodeInvalid
+			valid, er5 = s.RecoveryCodes.Verify(ctx, userId, info.Passcode)
+		} else {
+			valid, status, er5 = s.verifyPasscode(ctx, userId, info.Passcode, totp)
 		}
-		if !valid || er5 != nil {
+		if er5 != nil {
 			return result, er5
 		}
+		if !valid {
+			if totp || recovery {
+				if er6 := s.fail(ctx, info, *user); er6 != nil {
+					return result, er6
+				}
+			}
+			result.Status = status
+			return result, nil
+		}
+		deleteCode(ctx, s.CodeRepository, verifiedPrefix+userId)
 	}
 
 	de := user.Deactivated
@@ -243,6 +319,137 @@ func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthRe
 	return result, nil
 }
 
+func (s *Authenticator) verifyPasscode(ctx context.Context, id string, passcode string, totp bool) (bool, int, error) {
+	valid := false
+	status := s.Status.PasscodeInvalid
+	if totp {
+		v, err := s.TOTP.Verify(ctx, id, passcode)
+		if err != nil {
+			return false, status, err
+		}
+		valid = v
+	} else if s.CodeRepository != nil {
+		code, expiredAt, er4 := s.CodeRepository.Load(ctx, id)
+		if er4 != nil {
+			return false, status, er4
+		}
+		if len(code) > 0 {
+			if compareDate(expiredAt, time.Now()) < 0 {
+				deleteCode(ctx, s.CodeRepository, id)
+			} else {
+				v, exhausted, er5 := s.compareCode(ctx, id, passcode, code)
+				if er5 != nil {
+					return false, status, er5
+				}
+				if exhausted {
+					status = s.Status.PasscodeExhausted
+				}
+				valid = v
+			}
+		}
+	}
+	return valid, status, nil
+}
+
+// fail records a failed password or second factor, locking the user when the lockout policy says so.
+func (s *Authenticator) fail(ctx context.Context, info AuthInfo, user UserInfo) error {
+	// wrong passwords during an active lock are neither counted nor escalated
+	if user.LockedUntilTime != nil && compareDate(time.Now(), *user.LockedUntilTime) < 0 {
+		return nil
+	}
+	failCount := user.FailCount
+	var lockUntilTime *time.Time
+	lockout, lockedMinutes, maxPasswordFailed := s.lockout(ctx)
+	if lockout != nil {
+		count, lockedUntil := lockout.Fail(user, time.Now())
+		failCount = &count
+		lockUntilTime = lockedUntil
+	} else if lockedMinutes > 0 && maxPasswordFailed > 0 && user.FailCount != nil && *user.FailCount >= maxPasswordFailed {
+		l := time.Now().Add(time.Minute * time.Duration(lockedMinutes))
+		lockUntilTime = &l
+	}
+	lockUntilTime = KeepLock(user.LockedUntilTime, lockUntilTime, time.Now())
+	if err := s.Repository.Fail(ctx, user.Id, failCount, lockUntilTime); err != nil {
+		return err
+	}
+	if lockUntilTime != nil && s.Events != nil && (user.LockedUntilTime == nil || !lockUntilTime.Equal(*user.LockedUntilTime)) {
+		event := s.event(ctx, EventLockout, info, user.Id)
+		event.Status = s.Status.Locked
+		event.Reason = lockUntilTime.Format(time.RFC3339)
+		WriteEvent(ctx, s.Events, event)
+	}
+	return nil
+}
+
+// markVerified records that the first step succeeded; the second step is only accepted while this marker exists,
+// so a passcode, TOTP or recovery code never replaces the password.
+func (s *Authenticator) markVerified(ctx context.Context, id string) error {
+	if s.CodeRepository == nil {
+		return errors.New("two-factor requires a code repository to record the first step")
+	}
+	expires := s.CodeExpires
+	if expires <= 0 {
+		expires = 300
+	}
+	_, err := s.CodeRepository.Save(ctx, verifiedPrefix+id, "1", addSeconds(time.Now(), expires))
+	return err
+}
+
+func (s *Authenticator) isVerified(ctx context.Context, id string) (bool, error) {
+	if s.CodeRepository == nil {
+		return false, nil
+	}
+	marker, expiredAt, err := s.CodeRepository.Load(ctx, verifiedPrefix+id)
+	if err != nil || len(marker) == 0 {
+		return false, err
+	}
+	return compareDate(expiredAt, time.Now()) >= 0, nil
+}
+
+// compareCode counts the attempt before comparing, so concurrent guesses cannot exceed MaxCodeAttempts.
+// When MaxCodeAttempts is not set or the repository cannot count attempts, it keeps the original behavior:
+// the code is deleted once compared and a wrong code is reported as PasscodeInvalid.
+func (s *Authenticator) compareCode(ctx context.Context, id string, passcode string, code string) (bool, bool, error) {
+	counter, ok := s.CodeRepository.(CodeAttemptCounter)
+	if !ok || s.MaxCodeAttempts <= 0 {
+		valid, err := s.PasswordComparator.Compare(passcode, code)
+		if err != nil {
+			return false, false, err
+		}
+		deleteCode(ctx, s.CodeRepository, id)
+		return valid, false, nil
+	}
+	attempts, er1 := counter.IncreaseAttempts(ctx, id)
+	if er1 != nil {
+		return false, false, er1
+	}
+	if attempts <= 0 {
+		// the code was removed by a concurrent request after it was loaded
+		return false, false, nil
+	}
+	if attempts > s.MaxCodeAttempts {
+		deleteCode(ctx, s.CodeRepository, id)
+		return false, true, nil
+	}
+	valid, er2 := s.PasswordComparator.Compare(passcode, code)
+	if er2 != nil {
+		return false, false, er2
+	}
+	if valid || attempts >= s.MaxCodeAttempts {
+		deleteCode(ctx, s.CodeRepository, id)
+	}
+	return valid, !valid && attempts >= s.MaxCodeAttempts, nil
+}
+
+// resendThrottled reports whether the last code was sent less than CodeResendInterval seconds ago.
+func (s *Authenticator) resendThrottled(ctx context.Context, id string) (bool, error) {
+	sent, expiredAt, err := s.CodeRepository.Load(ctx, sentPrefix+id)
+	if err != nil || len(sent) == 0 {
+		return false, err
+	}
+	return time.Now().Before(expiredAt), nil
+}
+
 func deleteCode(ctx context.Context, codeService CodeRepository, id string) {
 	go func() {
 		timeOut := 30 * time.Second
@@ -277,6 +484,9 @@ func mapUserInfoToUserAccount(user UserInfo) UserAccount {
 	account.Gender = user.Gender
 	return account
 }
+func ToUserAccount(user UserInfo) UserAccount {
+	return mapUserInfoToUserAccount(user)
+}
 func FromContext(ctx context.Context, key string) string {
 	u := ctx.Value(key)
 	if u == nil {
@@ -296,6 +506,11 @@ func UserAccountToPayload(ctx context.Context, u *UserAccount, s PayloadConfig)
 			payload[s.Ip] = ip
 		}
 	}
+	if len(s.Tenant) > 0 {
+		if tenant := GetTenant(ctx); len(tenant) > 0 {
+			payload[s.Tenant] = tenant
+		}
+	}
 	if u == nil {
 		return payload
 	}
@@ -327,6 +542,11 @@ func ToPayload(ctx context.Context, user *UserInfo, s PayloadConfig) map[string]
 			payload[s.Ip] = ip
 		}
 	}
+	if len(s.Tenant) > 0 {
+		if tenant := GetTenant(ctx); len(tenant) > 0 {
+			payload[s.Tenant] = tenant
+		}
+	}
 	if user == nil {
 		return payload
 	}
@@ -365,3 +585,82 @@ func ToPayload(ctx context.Context, user *UserInfo, s PayloadConfig) map[string]
 	}
 	return payload
 }
+
+func (s *Authenticator) lockout(ctx context.Context) (LockoutPolicy, int, int) {
+	t, ok := s.Tenants[GetTenant(ctx)]
+	if !ok {
+		return s.Lockout, s.LockedMinutes, s.MaxPasswordFailed
+	}
+	lockedMinutes, maxPasswordFailed := s.LockedMinutes, s.MaxPasswordFailed
+	if t.LockedMinutes > 0 {
+		lockedMinutes = t.LockedMinutes
+	}
+	if t.MaxPasswordFailed > 0 {
+		maxPasswordFailed = t.MaxPasswordFailed
+	}
+	if t.Lockout != nil {
+		return NewLockoutPolicy(*t.Lockout), lockedMinutes, maxPasswordFailed
+	}
+	if t.LockedMinutes > 0 || t.MaxPasswordFailed > 0 {
+		return nil, lockedMinutes, maxPasswordFailed
+	}
+	return s.Lockout, lockedMinutes, maxPasswordFailed
+}
+
+func (s *Authenticator) Unlock(ctx context.Context, id string) error {
+	err := s.Repository.Pass(ctx, id, nil)
+	if err == nil && s.Events != nil {
+		WriteEvent(ctx, s.Events, AuthEvent{Type: EventUnlock, UserId: id})
+	}
+	return err
+}
+
+func (s *Authenticator) event(ctx context.Context, eventType string, info AuthInfo, userId string) AuthEvent {
+	ip := info.Ip
+	if len(s.Ip) > 0 {
+		if v := FromContext(ctx, s.Ip); len(v) > 0 {
+			ip = v
+		}
+	}
+	return AuthEvent{Type: eventType, UserId: userId, Username: info.Username, Ip: ip, Device: info.Device}
+}
+
+func (s *Authenticator) writeResult(ctx context.Context, info AuthInfo, result AuthResult, err error) {
+	var userId string
+	if result.User != nil {
+		userId = result.User.Id
+	}
+	success := err == nil && (result.Status == s.Status.Success || result.Status == s.Status.SuccessAndReactivated)
+	if success && info.Step > 0 {
+		event := s.event(ctx, EventTwoFactorVerified, info, userId)
+		event.Status = result.Status
+		WriteEvent(ctx, s.Events, event)
+	}
+	eventType := EventLoginFailure
+	if success {
+		eventType = EventLoginSuccess
+	} else if err == nil && info.Step <= 0 && result.Status == s.Status.TwoFactorRequired {
+		eventType = EventTwoFactorSent
+	}
+	event := s.event(ctx, eventType, info, userId)
+	event.Status = result.Status
+	if err != nil {
+		event.Reason = err.Error()
+	}
+	WriteEvent(ctx, s.Events, event)
+}
+
+func (s *Authenticator) rehash(ctx context.Context, id string, password string, hashed string) {
+	rehasher, ok1 := s.PasswordComparator.(PasswordRehasher)
+	updater, ok2 := s.Repository.(PasswordUpdater)
+	if !ok1 || !ok2 || !rehasher.NeedsRehash(hashed) {
+		return
+	}
+	newHash, err := s.PasswordComparator.Hash(password)
+	if err == nil {
+		_, err = updater.UpdatePassword(ctx, id, newHash)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,70 @@
+package authorizer
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	auth "github.com/core-go/authentication"
+)
+
+type PolicyAuthorizer struct {
+	Evaluate   func(req auth.AccessRequest) (bool, string)
+	Attributes []string
+	Ip         string
+	LogError   func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewPolicyAuthorizer(evaluate func(req auth.AccessRequest) (bool, string), attributes []string, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), opts ...string) *PolicyAuthorizer {
+	var ip string
+	if len(opts) > 0 {
+		ip = opts[0]
+	} else {
+		ip = "ip"
+	}
+	return &PolicyAuthorizer{Evaluate: evaluate, Attributes: attributes, Ip: ip, LogError: logError}
+}
+
+func (h *PolicyAuthorizer) check(ctx context.Context, action string, resourceType string, resource map[string]interface{}) (int, string) {
+	subject := make(map[string]interface{})
+	for _, key := range h.Attributes {
+		if v := ctx.Value(key); v != nil {
+			subject[key] = v
+		}
+	}
+	if len(subject) == 0 {
+		return http.StatusUnauthorized, "invalid subject"
+	}
+	ip, _ := ctx.Value(h.Ip).(string)
+	req := auth.AccessRequest{Action: action, ResourceType: resourceType, Subject: subject, Resource: resource, Environment: auth.NewEnvironment(ip, time.Now())}
+	if allowed, _ := h.Evaluate(req); !allowed {
+		return http.StatusForbidden, "no permission"
+	}
+	return http.StatusOK, ""
+}
+
+func (h *PolicyAuthorizer) logError(ctx context.Context, err error) {
+	if h.LogError != nil {
+		h.LogError(ctx, err.Error())
+	}
+}
+
+func (h *PolicyAuthorizer) Authorize(next http.Handler, action string, resourceType string, resource func(r *http.Request) (map[string]interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var attributes map[string]interface{}
+		if resource != nil {
+			var err error
+			attributes, err = resource(r)
+			if err != nil {
+				h.logError(r.Context(), err)
+				http.Error(w, "cannot load resource", http.StatusInternalServerError)
+				return
+			}
+		}
+		if code, msg := h.check(r.Context(), action, resourceType, attributes); code != http.StatusOK {
+			http.Error(w, msg, code)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,56 @@
+package authorizer
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	auth "github.com/core-go/authentication"
+)
+
+type PrivilegeAuthorizer struct {
+	Privileges func(ctx context.Context, id string) ([]auth.Privilege, error)
+	UserId     string
+	LogError   func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewPrivilegeAuthorizer(loadPrivileges func(ctx context.Context, id string) ([]auth.Privilege, error), ttl time.Duration, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), opts ...string) *PrivilegeAuthorizer {
+	var userId string
+	if len(opts) > 0 {
+		userId = opts[0]
+	} else {
+		userId = "userId"
+	}
+	if ttl > 0 {
+		loadPrivileges = auth.NewPrivilegesCache(loadPrivileges, ttl).Load
+	}
+	return &PrivilegeAuthorizer{Privileges: loadPrivileges, UserId: userId, LogError: logError}
+}
+
+func (h *PrivilegeAuthorizer) check(ctx context.Context, resource string, action int32) (int, string) {
+	userId, _ := ctx.Value(h.UserId).(string)
+	if len(userId) == 0 {
+		return http.StatusUnauthorized, "invalid user id"
+	}
+	privileges, err := h.Privileges(ctx, userId)
+	if err != nil {
+		if h.LogError != nil {
+			h.LogError(ctx, err.Error())
+		}
+		return http.StatusInternalServerError, "cannot load privileges"
+	}
+	if !auth.HasPermission(privileges, resource, action) {
+		return http.StatusForbidden, "no permission"
+	}
+	return http.StatusOK, ""
+}
+
+func (h *PrivilegeAuthorizer) Authorize(next http.Handler, resource string, action int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code, msg := h.check(r.Context(), resource, action); code != http.StatusOK {
+			http.Error(w, msg, code)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
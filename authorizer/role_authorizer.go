@@ -0,0 +1,47 @@
+package authorizer
+
+import (
+	"context"
+	"net/http"
+
+	auth "github.com/core-go/authentication"
+)
+
+type RoleAuthorizer struct {
+	HasRole func(userRoles []string, roles ...string) bool
+	Roles   string
+}
+
+func NewRoleAuthorizer(hasRole func(userRoles []string, roles ...string) bool, opts ...string) *RoleAuthorizer {
+	var roles string
+	if len(opts) > 0 {
+		roles = opts[0]
+	} else {
+		roles = "roles"
+	}
+	if hasRole == nil {
+		hasRole = auth.ContainsRole
+	}
+	return &RoleAuthorizer{HasRole: hasRole, Roles: roles}
+}
+
+func (h *RoleAuthorizer) check(ctx context.Context, roles []string) (int, string) {
+	userRoles := auth.ToRoles(ctx.Value(h.Roles))
+	if len(userRoles) == 0 {
+		return http.StatusUnauthorized, "invalid roles"
+	}
+	if !h.HasRole(userRoles, roles...) {
+		return http.StatusForbidden, "no permission"
+	}
+	return http.StatusOK, ""
+}
+
+func (h *RoleAuthorizer) RequireRole(next http.Handler, roles ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code, msg := h.check(r.Context(), roles); code != http.StatusOK {
+			http.Error(w, msg, code)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
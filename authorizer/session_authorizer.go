@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	auth "github.com/core-go/authentication"
 )
 
 type CachePort interface {
@@ -31,6 +33,12 @@ type SessionAuthorizer struct {
 	Cache              CachePort
 	sessionExpiredTime time.Duration
 	LogError           func(ctx context.Context, msg string, opts ...map[string]interface{})
+	Touch              func(ctx context.Context, userId string, sessionId string) error
+	Rotate             func(ctx context.Context, sessionId string) (string, error)
+	Resolve            func(ctx context.Context, sessionId string) (string, bool, error)
+	RotationInterval   time.Duration
+	Tenant             string
+	ResolveTenant      func(r *http.Request) string
 }
 
 func NewSessionAuthorizer(secretKey string, verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error),
@@ -109,7 +117,16 @@ func (h *SessionAuthorizer) Authorize(next http.Handler, skipRefreshTTL bool) ht
 		ctx := r.Context()
 		if h.Cache != nil {
 			var sessionData map[string]string
+			moved := false
 			s, err := h.Cache.Get(r.Context(), sessionId)
+			if err != nil && h.Resolve != nil {
+				// a parallel request may have just rotated the id, so follow the alias to the new session
+				if newId, ok, er1 := h.Resolve(ctx, sessionId); er1 == nil && ok {
+					sessionId = newId
+					moved = true
+					s, err = h.Cache.Get(ctx, sessionId)
+				}
+			}
 			if err != nil {
 				http.Error(w, "Session is expired", http.StatusUnauthorized)
 				return
@@ -159,6 +176,39 @@ func (h *SessionAuthorizer) Authorize(next http.Handler, skipRefreshTTL bool) ht
 					return
 				}
 			}
+			if h.Rotate != nil && !moved && auth.NeedRotation(sessionData, h.RotationInterval, time.Now()) {
+				newId, err3 := h.Rotate(ctx, sessionId)
+				if err3 == auth.ErrSessionNotFound {
+					// a flagged session was rotated by a parallel request, which leaves no alias to the new id
+					http.Error(w, "Session is expired", http.StatusUnauthorized)
+					return
+				}
+				if err3 != nil {
+					if h.LogError != nil {
+						h.LogError(ctx, err3.Error())
+					}
+					http.Error(w, "cannot rotate session id", http.StatusInternalServerError)
+					return
+				}
+				sessionId = newId
+				moved = true
+			}
+			if moved && h.RefreshExpire != nil {
+				encodedId := sessionId
+				if h.EncodeSessionID != nil {
+					encodedId = h.EncodeSessionID(sessionId)
+				}
+				if err4 := h.RefreshExpire(w, encodedId); err4 != nil {
+					http.Error(w, "error to refresh expire sessionId", http.StatusInternalServerError)
+					return
+				}
+			}
+			ctx = context.WithValue(ctx, h.SId, sessionId)
+			if h.Touch != nil {
+				if err3 := h.Touch(ctx, sessionData[h.Id], sessionId); err3 != nil && h.LogError != nil {
+					h.LogError(ctx, err3.Error())
+				}
+			}
 			azureToken := getString(sessionData, "azure_token")
 			ctx = context.WithValue(ctx, "azure_token", azureToken)
 
@@ -186,6 +236,18 @@ func (h *SessionAuthorizer) Verify(next http.Handler, skipRefreshTTL bool, sessi
 		if len(ip) == 0 {
 			ip = getRemoteIp(r)
 		}
+		if len(h.Tenant) > 0 {
+			tenant := auth.GetTenant(ctx)
+			if h.ResolveTenant != nil {
+				tenant = h.ResolveTenant(r)
+			}
+			claim, _ := payload[h.Tenant].(string)
+			if len(claim) == 0 || len(tenant) > 0 && claim != tenant {
+				http.Error(writer, "invalid tenant", http.StatusForbidden)
+				return
+			}
+			ctx = auth.WithTenant(ctx, claim)
+		}
 		ctx = context.WithValue(ctx, "ip", ip)
 		for k, e := range payload {
 			if len(k) > 0 {
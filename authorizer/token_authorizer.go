@@ -0,0 +1,110 @@
+package authorizer
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	auth "github.com/core-go/authentication"
+)
+
+type TokenAuthorizer struct {
+	Secret         string
+	VerifyToken    func(tokenString string, secret string) (map[string]interface{}, int64, int64, error)
+	CheckBlacklist func(ctx context.Context, id string, token string, createAt time.Time) string
+	CheckWhitelist func(ctx context.Context, id string, token string) bool
+	Id             string
+	Ip             string
+	Tenant         string
+	ResolveTenant  func(r *http.Request) string
+}
+
+func NewTokenAuthorizer(secret string, verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error),
+	checkBlacklist func(ctx context.Context, id string, token string, createAt time.Time) string,
+	opts ...string) *TokenAuthorizer {
+	return NewTokenAuthorizerWithWhitelist(secret, verifyToken, checkBlacklist, nil, opts...)
+}
+func NewTokenAuthorizerWithWhitelist(secret string, verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error),
+	checkBlacklist func(ctx context.Context, id string, token string, createAt time.Time) string,
+	checkWhitelist func(ctx context.Context, id string, token string) bool,
+	opts ...string) *TokenAuthorizer {
+	var id, ip string
+	if len(opts) > 0 {
+		id = opts[0]
+	} else {
+		id = "id"
+	}
+	if len(opts) > 1 {
+		ip = opts[1]
+	} else {
+		ip = "ip"
+	}
+	return &TokenAuthorizer{
+		Secret:         secret,
+		VerifyToken:    verifyToken,
+		CheckBlacklist: checkBlacklist,
+		CheckWhitelist: checkWhitelist,
+		Id:             id,
+		Ip:             ip,
+	}
+}
+
+func (h *TokenAuthorizer) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := getBearerToken(r.Header.Get("Authorization"))
+		if len(token) == 0 {
+			http.Error(w, "invalid authorization token", http.StatusUnauthorized)
+			return
+		}
+		payload, iat, _, err := h.VerifyToken(token, h.Secret)
+		if err != nil {
+			http.Error(w, "invalid authorization token", http.StatusUnauthorized)
+			return
+		}
+		ctx := r.Context()
+		id, _ := payload[h.Id].(string)
+		if h.CheckBlacklist != nil {
+			reason := h.CheckBlacklist(ctx, id, token, time.Unix(iat, 0))
+			if len(reason) > 0 {
+				http.Error(w, reason, http.StatusUnauthorized)
+				return
+			}
+		}
+		if h.CheckWhitelist != nil && !h.CheckWhitelist(ctx, id, token) {
+			http.Error(w, "invalid authorization token", http.StatusUnauthorized)
+			return
+		}
+		if len(h.Tenant) > 0 {
+			tenant := auth.GetTenant(ctx)
+			if h.ResolveTenant != nil {
+				tenant = h.ResolveTenant(r)
+			}
+			claim, _ := payload[h.Tenant].(string)
+			if len(claim) == 0 || len(tenant) > 0 && claim != tenant {
+				http.Error(w, "invalid tenant", http.StatusForbidden)
+				return
+			}
+			ctx = auth.WithTenant(ctx, claim)
+		}
+		ip := getForwardedRemoteIp(r)
+		if len(ip) == 0 {
+			ip = getRemoteIp(r)
+		}
+		ctx = context.WithValue(ctx, "token", token)
+		ctx = context.WithValue(ctx, h.Ip, ip)
+		for k, e := range payload {
+			if len(k) > 0 {
+				ctx = context.WithValue(ctx, k, e)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func getBearerToken(authorization string) string {
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(authorization[7:])
+}
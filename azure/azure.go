@@ -5,9 +5,9 @@ import (
 	"crypto/rsa"
 	"errors"
 	"fmt"
-	"strings"
 
 	auth "github.com/core-go/authentication"
+	tokens "github.com/core-go/authentication/jwt"
 	"github.com/golang-jwt/jwt"
 	"github.com/lestrrat-go/jwx/jwa"
 	"github.com/lestrrat-go/jwx/jwk"
@@ -50,7 +50,13 @@ func NewAzureAuthenticator(
 	return &Authenticator{getUserByToken, userPort, privileges, generateToken, tokenConfig, config, id}
 }
 
-const expired = "Token is expired"
+func isExpired(err error) bool {
+	var ve *jwt.ValidationError
+	if errors.As(err, &ve) {
+		return ve.Errors&jwt.ValidationErrorExpired != 0
+	}
+	return errors.Is(err, tokens.ErrTokenExpired)
+}
 
 // Authenticate authorization jwt here doesn't contain prefix bearer
 func (a Authenticator) Authenticate(ctx context.Context, authorization string) (*auth.UserAccount, bool, error) {
@@ -59,7 +65,7 @@ func (a Authenticator) Authenticate(ctx context.Context, authorization string) (
 	}
 	azureToken, er1 := VerifyAzureADJWT(ctx, authorization)
 	if er1 != nil {
-		if strings.Contains(er1.Error(), expired) {
+		if isExpired(er1) {
 			return nil, true, nil
 		}
 		return nil, false, er1
@@ -67,7 +73,7 @@ func (a Authenticator) Authenticate(ctx context.Context, authorization string) (
 
 	azureID, er2 := VerifyAzureADJWTClaims(azureToken, a.Config.TenantId, a.Config.ClientId)
 	if er2 != nil {
-		if strings.Contains(er2.Error(), expired) {
+		if isExpired(er2) {
 			return nil, true, nil
 		}
 		return nil, false, er2
@@ -83,7 +89,7 @@ func (a Authenticator) Authenticate(ctx context.Context, authorization string) (
 	if !exist {
 		azureUser, er4 := a.GetUserByToken(ctx, authorization)
 		if er4 != nil {
-			if strings.Contains(er4.Error(), expired) {
+			if isExpired(er4) {
 				return nil, true, nil
 			}
 			return nil, false, er4
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	auth "github.com/core-go/authentication"
+	"github.com/core-go/authentication/random"
 )
 
 const internalServerError = "Internal Server Error"
@@ -90,6 +91,9 @@ func NewAuthenticationHandlerWithCache(authenticate func(ctx context.Context, au
 	} else {
 		logoutAction = "logout"
 	}
+	if generate == nil {
+		generate = random.SessionId
+	}
 	return &AuthenticationHandler{Auth: authenticate, Resource: resource, Action: action, Error: logError, Ip: ip, Id: id, SId: sid, UserId: userId, CookieName: cookieName, PrefixSessionIndex: prefixSessionIndex, Log: writeLog, Cache: cache, Generate: generate, Expired: expired, Host: host, SingleSession: singleSession, SameSite: sameSite, LogoutAction: logoutAction}
 }
 func NewAuthenticationHandler(authenticate func(ctx context.Context, authorization string) (*auth.UserAccount, bool, error), logError func(context.Context, string, ...map[string]interface{}), options ...func(context.Context, string, string, bool, string) error) *AuthenticationHandler {
@@ -227,7 +231,7 @@ func (h *AuthenticationHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 	data, err := GetCookie(r.Context(), sValue, h.SId, h.Cache.Get)
 	if err != nil {
-		if err.Error() == "redis: nil" {
+		if auth.IsCacheMiss(err) {
 			respond(w, r, http.StatusOK, 1, h.Log, h.Resource, h.LogoutAction, true, err.Error())
 			return
 		}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+type HashPrefixChecker struct {
+	Path string
+	Min  int64
+}
+
+func NewHashPrefixChecker(path string, options ...int64) *HashPrefixChecker {
+	var min int64
+	if len(options) > 0 && options[0] > 0 {
+		min = options[0]
+	} else {
+		min = 1
+	}
+	return &HashPrefixChecker{Path: path, Min: min}
+}
+
+func (c *HashPrefixChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return c.scan(c.Path, hash)
+	}
+	file := filepath.Join(c.Path, prefix)
+	if _, err = os.Stat(file); os.IsNotExist(err) {
+		file = file + ".txt"
+		if _, err = os.Stat(file); os.IsNotExist(err) {
+			return false, nil
+		}
+	}
+	return c.scan(file, suffix)
+}
+
+func (c *HashPrefixChecker) scan(file string, hash string) (bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.Index(line, ":")
+		value := line
+		var count int64 = 1
+		if i >= 0 {
+			value = line[:i]
+			if n, er1 := strconv.ParseInt(strings.TrimSpace(line[i+1:]), 10, 64); er1 == nil {
+				count = n
+			}
+		}
+		if strings.EqualFold(value, hash) {
+			return count >= c.Min, nil
+		}
+	}
+	return false, scanner.Err()
+}
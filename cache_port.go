@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -10,3 +11,14 @@ type CachePort interface {
 	GetMany(ctx context.Context, key []string) (map[string]string, []string, error)
 	Get(ctx context.Context, key string) (string, error)
 }
+
+// CacheAdder is implemented by caches with an atomic set-if-absent, such as the redis SETNX command.
+type CacheAdder interface {
+	Add(ctx context.Context, key string, value string, timeToLive time.Duration) (bool, error)
+}
+
+// IsCacheMiss reports a missing key: ErrCacheMiss from MemoryStore, or the go-redis Nil reply, which is matched by
+// its message because this package does not depend on go-redis.
+func IsCacheMiss(err error) bool {
+	return err != nil && (errors.Is(err, ErrCacheMiss) || err.Error() == "redis: nil")
+}
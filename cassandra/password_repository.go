@@ -0,0 +1,189 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	a "github.com/core-go/authentication"
+	"github.com/gocql/gocql"
+)
+
+type PasswordRepository struct {
+	Session                 *gocql.Session
+	userTableName           string
+	passwordTableName       string
+	historyTableName        string
+	UserName                string
+	UserId                  string
+	PasswordName            string
+	PasswordChangedTimeName string
+	FailCountName           string
+	LockedUntilTimeName     string
+	ContactName             string
+	EmailName               string
+	DisplayNameName         string
+	LanguageName            string
+}
+
+func NewPasswordAdapterByConfig(session *gocql.Session, userTableName, passwordTableName, historyTableName string, c a.SchemaConfig) *PasswordRepository {
+	return NewPasswordRepositoryByConfig(session, userTableName, passwordTableName, historyTableName, c)
+}
+func NewPasswordRepositoryByConfig(session *gocql.Session, userTableName, passwordTableName, historyTableName string, c a.SchemaConfig) *PasswordRepository {
+	if len(passwordTableName) == 0 {
+		passwordTableName = userTableName
+	}
+	userName := c.Username
+	if len(userName) == 0 {
+		userName = "username"
+	}
+	userId := c.UserId
+	if len(userId) == 0 {
+		userId = "userid"
+	}
+	passwordName := c.Password
+	if len(passwordName) == 0 {
+		passwordName = "password"
+	}
+	return &PasswordRepository{
+		Session:                 session,
+		userTableName:           strings.ToLower(userTableName),
+		passwordTableName:       strings.ToLower(passwordTableName),
+		historyTableName:        strings.ToLower(historyTableName),
+		UserName:                strings.ToLower(userName),
+		UserId:                  strings.ToLower(userId),
+		PasswordName:            strings.ToLower(passwordName),
+		PasswordChangedTimeName: strings.ToLower(c.PasswordChangedTime),
+		FailCountName:           strings.ToLower(c.FailCount),
+		LockedUntilTimeName:     strings.ToLower(c.LockedUntilTime),
+		ContactName:             strings.ToLower(c.Contact),
+		EmailName:               strings.ToLower(c.Email),
+		DisplayNameName:         strings.ToLower(c.DisplayName),
+		LanguageName:            strings.ToLower(c.Language),
+	}
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*a.UserInfo, error) {
+	row, err := r.findUser(ctx, r.UserName, contact)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil && len(r.EmailName) > 0 {
+		row, err = r.findUser(ctx, r.EmailName, contact)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if row == nil {
+		return nil, nil
+	}
+	user := a.UserInfo{}
+	if id, ok := row[r.UserId].(string); ok {
+		user.Id = id
+	}
+	if username, ok := row[r.UserName].(string); ok {
+		user.Username = username
+	}
+	if len(r.EmailName) > 0 {
+		if email, ok := row[r.EmailName].(string); ok {
+			user.Email = &email
+		}
+	}
+	if len(r.ContactName) > 0 {
+		if contact, ok := row[r.ContactName].(string); ok {
+			user.Contact = &contact
+		}
+	}
+	if len(r.DisplayNameName) > 0 {
+		if displayName, ok := row[r.DisplayNameName].(string); ok {
+			user.DisplayName = &displayName
+		}
+	}
+	if len(r.LanguageName) > 0 {
+		if language, ok := row[r.LanguageName].(string); ok {
+			user.Language = &language
+		}
+	}
+	if r.userTableName != r.passwordTableName {
+		row = make(map[string]interface{})
+		query := "SELECT " + r.PasswordName + " FROM " + r.passwordTableName + " WHERE " + r.UserId + " = ?"
+		if err = r.Session.Query(query, user.Id).WithContext(ctx).MapScan(row); err != nil {
+			if err == gocql.ErrNotFound {
+				return &user, nil
+			}
+			return nil, err
+		}
+	}
+	if password, ok := row[r.PasswordName].(string); ok {
+		user.Password = password
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) findUser(ctx context.Context, column string, value string) (map[string]interface{}, error) {
+	row := make(map[string]interface{})
+	query := "SELECT * FROM " + r.userTableName + " WHERE " + column + " = ? LIMIT 1 ALLOW FILTERING"
+	if err := r.Session.Query(query, value).WithContext(ctx).MapScan(row); err != nil {
+		if err == gocql.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return row, nil
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if len(r.historyTableName) == 0 || max <= 0 {
+		return passwords, nil
+	}
+	query := "SELECT " + r.PasswordName + " FROM " + r.historyTableName + " WHERE " + r.UserId + " = ? ORDER BY " + r.historyTimeName() + " DESC LIMIT ?"
+	iter := r.Session.Query(query, id, max).WithContext(ctx).Iter()
+	var password string
+	for iter.Scan(&password) {
+		passwords = append(passwords, password)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return passwords, nil
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	columns := []string{r.PasswordName + " = ?"}
+	values := []interface{}{newPassword}
+	if len(r.PasswordChangedTimeName) > 0 {
+		columns = append(columns, r.PasswordChangedTimeName+" = ?")
+		values = append(values, now)
+	}
+	if len(r.FailCountName) > 0 {
+		columns = append(columns, r.FailCountName+" = 0")
+	}
+	if len(r.LockedUntilTimeName) > 0 {
+		columns = append(columns, r.LockedUntilTimeName+" = null")
+	}
+	values = append(values, id)
+	query := "UPDATE " + r.passwordTableName + " SET " + strings.Join(columns, ", ") + " WHERE " + r.UserId + " = ? IF EXISTS"
+	applied, err := r.Session.Query(query, values...).WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return -1, err
+	}
+	if !applied {
+		return 0, nil
+	}
+	if len(r.historyTableName) > 0 {
+		insert := "INSERT INTO " + r.historyTableName + " (" + r.UserId + ", " + r.PasswordName + ", " + r.historyTimeName() + ") VALUES (?, ?, ?)"
+		if err = r.Session.Query(insert, id, newPassword, now).WithContext(ctx).Exec(); err != nil {
+			return -1, err
+		}
+	}
+	return 1, nil
+}
+
+func (r *PasswordRepository) historyTimeName() string {
+	if len(r.PasswordChangedTimeName) > 0 {
+		return r.PasswordChangedTimeName
+	}
+	return "passwordchangedtime"
+}
@@ -39,10 +39,13 @@ type UserRepository struct {
 	AccessTimeFromName      string
 	AccessTimeToName        string
 	TwoFactorsName          string
+	TenantName              string
 }
 
 func NewUserRepositoryByConfig(session *gocql.Session, userTableName, passwordTableName string, activatedStatus string, status a.UserStatusConfig, c a.SchemaConfig, options ...func(context.Context, string) (bool, error)) *UserRepository {
-	return NewUserRepository(session, userTableName, passwordTableName, activatedStatus, status, c.Id, c.Username, c.UserId, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r := NewUserRepository(session, userTableName, passwordTableName, activatedStatus, status, c.Id, c.Username, c.UserId, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r.TenantName = strings.ToLower(c.Tenant)
+	return r
 }
 func NewUserAdapterByConfig(session *gocql.Session, userTableName, passwordTableName string, activatedStatus string, status a.UserStatusConfig, c a.SchemaConfig, options ...func(context.Context, string) (bool, error)) *UserRepository {
 	return NewUserRepositoryByConfig(session, userTableName, passwordTableName, activatedStatus, status, c, options...)
@@ -86,8 +89,17 @@ func NewUserRepository(session *gocql.Session, userTableName, passwordTableName
 func (r *UserRepository) GetUser(ctx context.Context, username string) (*a.UserInfo, error) {
 	session := r.Session
 	userInfo := a.UserInfo{}
-	query := "SELECT * FROM " + r.userTableName + " WHERE " + r.UserName + " = ? ALLOW FILTERING"
-	raws := session.Query(query, username).Iter()
+	query := "SELECT * FROM " + r.userTableName + " WHERE " + r.UserName + " = ?"
+	params := []interface{}{username}
+	if len(r.TenantName) > 0 {
+		tenant := a.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		query = query + " AND " + r.TenantName + " = ?"
+		params = append(params, tenant)
+	}
+	raws := session.Query(query+" ALLOW FILTERING", params...).Iter()
 	userInfo.Username = username
 	for {
 		// New map each iteration
@@ -267,9 +279,9 @@ func (r *UserRepository) Fail(ctx context.Context, userId string, failCount *int
 	}
 	if failCount != nil && len(r.FailCountName) > 0 {
 		pass[r.FailCountName] = *failCount + 1
-		if len(r.LockedUntilTimeName) > 0 {
-			pass[r.LockedUntilTimeName] = lockedUntil
-		}
+	}
+	if len(r.LockedUntilTimeName) > 0 && lockedUntil != nil {
+		pass[r.LockedUntilTimeName] = lockedUntil
 	}
 	query := map[string]interface{}{
 		r.IdName: userId,
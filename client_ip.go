@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIp returns the transport address of the request. X-Forwarded-For is only honored when the request comes from
+// one of trustedProxies (IPs or CIDRs); it is then read from the right, skipping the trusted hops.
+func ClientIp(r *http.Request, trustedProxies []string) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if len(trustedProxies) == 0 || !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if strings.Contains(proxy, "/") {
+			if _, network, err := net.ParseCIDR(proxy); err == nil && network.Contains(parsed) {
+				return true
+			}
+		} else if p := net.ParseIP(proxy); p != nil && p.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
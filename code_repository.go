@@ -10,3 +10,8 @@ type CodeRepository interface {
 	Load(ctx context.Context, id string) (string, time.Time, error)
 	Delete(ctx context.Context, id string) (int64, error)
 }
+
+// CodeAttemptCounter is optionally implemented by a CodeRepository to count verification attempts of the stored code; Save resets the count.
+type CodeAttemptCounter interface {
+	IncreaseAttempts(ctx context.Context, id string) (int, error)
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	SenderEmail = "email"
+	SenderSMS   = "sms"
+	SenderVoice = "voice"
+)
+
+type CodeMessage struct {
+	Channel     string
+	To          string
+	Code        string
+	ExpireAt    time.Time
+	Expires     int64
+	Username    string
+	DisplayName string
+	Language    string
+}
+
+type Sender interface {
+	Send(ctx context.Context, msg CodeMessage) error
+}
+
+type CodeTemplate struct {
+	Subject string `yaml:"subject" mapstructure:"subject" json:"subject,omitempty" gorm:"column:subject" bson:"subject,omitempty" dynamodbav:"subject,omitempty" firestore:"subject,omitempty"`
+	Body    string `yaml:"body" mapstructure:"body" json:"body,omitempty" gorm:"column:body" bson:"body,omitempty" dynamodbav:"body,omitempty" firestore:"body,omitempty"`
+}
+
+type codeTemplate struct {
+	Subject *template.Template
+	Body    executor
+}
+
+// executor is implemented by both text/template and html/template.
+type executor interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// CodeTemplates holds the templates of a channel keyed by language; the template with the empty key is the default.
+type CodeTemplates struct {
+	templates map[string]codeTemplate
+}
+
+func NewCodeTemplates(templates map[string]CodeTemplate) (*CodeTemplates, error) {
+	return newCodeTemplates(templates, false)
+}
+
+// NewHtmlCodeTemplates parses the bodies with html/template, so the message fields are escaped in HTML mail.
+func NewHtmlCodeTemplates(templates map[string]CodeTemplate) (*CodeTemplates, error) {
+	return newCodeTemplates(templates, true)
+}
+
+func newCodeTemplates(templates map[string]CodeTemplate, html bool) (*CodeTemplates, error) {
+	t := &CodeTemplates{templates: make(map[string]codeTemplate)}
+	for language, c := range templates {
+		subject, er1 := template.New("subject").Parse(c.Subject)
+		if er1 != nil {
+			return nil, er1
+		}
+		var body executor
+		var er2 error
+		if html {
+			body, er2 = htmltemplate.New("body").Parse(c.Body)
+		} else {
+			body, er2 = template.New("body").Parse(c.Body)
+		}
+		if er2 != nil {
+			return nil, er2
+		}
+		t.templates[strings.ToLower(language)] = codeTemplate{Subject: subject, Body: body}
+	}
+	return t, nil
+}
+
+func (t *CodeTemplates) get(language string) (codeTemplate, bool) {
+	language = strings.ToLower(language)
+	for len(language) > 0 {
+		if c, ok := t.templates[language]; ok {
+			return c, true
+		}
+		i := strings.LastIndexAny(language, "-_")
+		if i < 0 {
+			break
+		}
+		language = language[:i]
+	}
+	c, ok := t.templates[""]
+	return c, ok
+}
+
+func (t *CodeTemplates) Render(msg CodeMessage) (string, string, error) {
+	c, ok := t.get(msg.Language)
+	if !ok {
+		return "", msg.Code, nil
+	}
+	var subject, body bytes.Buffer
+	if err := c.Subject.Execute(&subject, msg); err != nil {
+		return "", "", err
+	}
+	if err := c.Body.Execute(&body, msg); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
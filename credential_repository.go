@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type CredentialRepository interface {
+	Load(ctx context.Context, userId string) ([]WebAuthnCredential, error)
+	Get(ctx context.Context, id string) (*WebAuthnCredential, error)
+	Save(ctx context.Context, credential WebAuthnCredential) (int64, error)
+	Update(ctx context.Context, id string, signCount int64, lastUsedTime time.Time) (int64, error)
+	Delete(ctx context.Context, userId string, id string) (int64, error)
+}
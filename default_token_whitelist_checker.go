@@ -53,7 +53,10 @@ func (b *DefaultTokenWhitelistChecker) Check(ctx context.Context, id string, tok
 		return false
 	}
 	if len(value) > 0 {
-		tokenStore, _ := strconv.Unquote(value)
+		tokenStore, er0 := strconv.Unquote(value)
+		if er0 != nil {
+			tokenStore = value
+		}
 		if b.Level != 0 {
 			if tokenStore != token {
 				return false
@@ -0,0 +1,161 @@
+package dynamodb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// CodeRepository stores the expiry as unix seconds, so ExpiredAtName can also be used as the table's TTL attribute.
+type CodeRepository struct {
+	Db            *dynamodb.DynamoDB
+	TableName     string
+	CodeName      string
+	ExpiredAtName string
+	AttemptsName  string
+}
+
+func NewCodeAdapter(db *dynamodb.DynamoDB, tableName string, opts ...string) *CodeRepository {
+	return NewCodeRepository(db, tableName, opts...)
+}
+func NewCodeRepository(db *dynamodb.DynamoDB, tableName string, opts ...string) *CodeRepository {
+	var codeName, expiredAtName, attemptsName string
+	if len(opts) > 0 && len(opts[0]) > 0 {
+		codeName = opts[0]
+	} else {
+		codeName = "code"
+	}
+	if len(opts) > 1 && len(opts[1]) > 0 {
+		expiredAtName = opts[1]
+	} else {
+		expiredAtName = "expiredAt"
+	}
+	if len(opts) > 2 && len(opts[2]) > 0 {
+		attemptsName = opts[2]
+	} else {
+		attemptsName = "attempts"
+	}
+	if len(tableName) == 0 {
+		tableName = "passcodes"
+	}
+	return &CodeRepository{Db: db, TableName: tableName, CodeName: codeName, ExpiredAtName: expiredAtName, AttemptsName: attemptsName}
+}
+
+func (r *CodeRepository) Save(ctx context.Context, id string, code string, expireAt time.Time) (int64, error) {
+	item := map[string]interface{}{"_id": id, r.CodeName: code, r.ExpiredAtName: expireAt.Unix(), r.AttemptsName: 0}
+	return upsertOne(ctx, r.Db, r.TableName, item)
+}
+
+func (r *CodeRepository) Load(ctx context.Context, id string) (string, time.Time, error) {
+	key, er1 := dynamodbattribute.MarshalMap(map[string]interface{}{"_id": id})
+	if er1 != nil {
+		return "", time.Time{}, er1
+	}
+	output, er2 := r.Db.GetItemWithContext(ctx, &dynamodb.GetItemInput{TableName: aws.String(r.TableName), Key: key, ConsistentRead: aws.Bool(true)})
+	if er2 != nil || len(output.Item) == 0 {
+		return "", time.Time{}, er2
+	}
+	raw := make(map[string]interface{})
+	if er3 := dynamodbattribute.UnmarshalMap(output.Item, &raw); er3 != nil {
+		return "", time.Time{}, er3
+	}
+	code, _ := raw[r.CodeName].(string)
+	seconds, ok := raw[r.ExpiredAtName].(float64)
+	if !ok {
+		return "", time.Time{}, nil
+	}
+	expiredAt := time.Unix(int64(seconds), 0)
+	if expiredAt.Before(time.Now()) {
+		return "", time.Time{}, nil
+	}
+	return code, expiredAt, nil
+}
+
+func (r *CodeRepository) IncreaseAttempts(ctx context.Context, id string) (int, error) {
+	key, er1 := dynamodbattribute.MarshalMap(map[string]interface{}{"_id": id})
+	if er1 != nil {
+		return 0, er1
+	}
+	update := expression.Add(expression.Name(r.AttemptsName), expression.Value(1))
+	cond := expression.AttributeExists(expression.Name("_id"))
+	expr, er2 := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if er2 != nil {
+		return 0, er2
+	}
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.TableName),
+		Key:                       key,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ReturnValues:              aws.String(dynamodb.ReturnValueUpdatedNew),
+	}
+	output, er3 := r.Db.UpdateItemWithContext(ctx, input)
+	if er3 != nil {
+		if strings.Index(er3.Error(), "ConditionalCheckFailedException:") >= 0 {
+			return 0, nil
+		}
+		return 0, er3
+	}
+	var attempts int
+	if v, ok := output.Attributes[r.AttemptsName]; ok {
+		if er4 := dynamodbattribute.Unmarshal(v, &attempts); er4 != nil {
+			return 0, er4
+		}
+	}
+	return attempts, nil
+}
+
+func (r *CodeRepository) Delete(ctx context.Context, id string) (int64, error) {
+	key, er1 := dynamodbattribute.MarshalMap(map[string]interface{}{"_id": id})
+	if er1 != nil {
+		return 0, er1
+	}
+	output, er2 := r.Db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{TableName: aws.String(r.TableName), Key: key, ReturnValues: aws.String(dynamodb.ReturnValueAllOld)})
+	if er2 != nil {
+		return 0, er2
+	}
+	if len(output.Attributes) == 0 {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (r *CodeRepository) Purge(ctx context.Context) (int64, error) {
+	filter := expression.LessThan(expression.Name(r.ExpiredAtName), expression.Value(time.Now().Unix()))
+	expr, er0 := expression.NewBuilder().WithFilter(filter).WithProjection(expression.NamesList(expression.Name("_id"))).Build()
+	if er0 != nil {
+		return 0, er0
+	}
+	query := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.TableName),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	var count int64
+	for {
+		output, er1 := r.Db.ScanWithContext(ctx, query)
+		if er1 != nil {
+			return count, er1
+		}
+		for _, item := range output.Items {
+			_, er2 := r.Db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{TableName: aws.String(r.TableName), Key: map[string]*dynamodb.AttributeValue{"_id": item["_id"]}})
+			if er2 != nil {
+				return count, er2
+			}
+			count++
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			return count, nil
+		}
+		query.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,205 @@
+package dynamodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	auth "github.com/core-go/authentication"
+)
+
+type PasswordRepository struct {
+	Db                      *dynamodb.DynamoDB
+	UserTableName           string
+	PasswordTableName       string
+	HistoryTableName        string
+	UserName                string
+	UserIdName              string
+	PasswordName            string
+	PasswordChangedTimeName string
+	FailCountName           string
+	LockedUntilTimeName     string
+	ContactName             string
+	EmailName               string
+	DisplayNameName         string
+	LanguageName            string
+}
+
+func NewPasswordAdapterByConfig(dynamoDB *dynamodb.DynamoDB, userTableName, passwordTableName, historyTableName string, c auth.SchemaConfig) *PasswordRepository {
+	return NewPasswordRepositoryByConfig(dynamoDB, userTableName, passwordTableName, historyTableName, c)
+}
+func NewPasswordRepositoryByConfig(dynamoDB *dynamodb.DynamoDB, userTableName, passwordTableName, historyTableName string, c auth.SchemaConfig) *PasswordRepository {
+	if len(passwordTableName) == 0 {
+		passwordTableName = userTableName
+	}
+	userName := c.Username
+	if len(userName) == 0 {
+		userName = "username"
+	}
+	userIdName := c.UserId
+	if len(userIdName) == 0 {
+		userIdName = "userId"
+	}
+	passwordName := c.Password
+	if len(passwordName) == 0 {
+		passwordName = "password"
+	}
+	return &PasswordRepository{
+		Db:                      dynamoDB,
+		UserTableName:           userTableName,
+		PasswordTableName:       passwordTableName,
+		HistoryTableName:        historyTableName,
+		UserName:                userName,
+		UserIdName:              userIdName,
+		PasswordName:            passwordName,
+		PasswordChangedTimeName: c.PasswordChangedTime,
+		FailCountName:           c.FailCount,
+		LockedUntilTimeName:     c.LockedUntilTime,
+		ContactName:             c.Contact,
+		EmailName:               c.Email,
+		DisplayNameName:         c.DisplayName,
+		LanguageName:            c.Language,
+	}
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*auth.UserInfo, error) {
+	filter := expression.Equal(expression.Name(r.UserName), expression.Value(contact))
+	if len(r.EmailName) > 0 {
+		filter = filter.Or(expression.Equal(expression.Name(r.EmailName), expression.Value(contact)))
+	}
+	expr, _ := expression.NewBuilder().WithFilter(filter).Build()
+	query := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.UserTableName),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	output, er1 := r.Db.ScanWithContext(ctx, query)
+	if er1 != nil || len(output.Items) == 0 {
+		return nil, er1
+	}
+	raw := make(map[string]interface{})
+	if er1 = dynamodbattribute.UnmarshalMap(output.Items[0], &raw); er1 != nil {
+		return nil, er1
+	}
+	user := auth.UserInfo{}
+	if id, ok := raw["_id"].(string); ok {
+		user.Id = id
+	}
+	if username, ok := raw[r.UserName].(string); ok {
+		user.Username = username
+	}
+	if len(r.EmailName) > 0 {
+		if email, ok := raw[r.EmailName].(string); ok {
+			user.Email = &email
+		}
+	}
+	if len(r.ContactName) > 0 {
+		if contact, ok := raw[r.ContactName].(string); ok {
+			user.Contact = &contact
+		}
+	}
+	if len(r.DisplayNameName) > 0 {
+		if displayName, ok := raw[r.DisplayNameName].(string); ok {
+			user.DisplayName = &displayName
+		}
+	}
+	if len(r.LanguageName) > 0 {
+		if language, ok := raw[r.LanguageName].(string); ok {
+			user.Language = &language
+		}
+	}
+	if r.UserTableName != r.PasswordTableName {
+		key, er2 := dynamodbattribute.MarshalMap(map[string]interface{}{"_id": user.Id})
+		if er2 != nil {
+			return nil, er2
+		}
+		outputPassword, er3 := r.Db.GetItemWithContext(ctx, &dynamodb.GetItemInput{TableName: aws.String(r.PasswordTableName), Key: key})
+		if er3 != nil {
+			return nil, er3
+		}
+		raw = make(map[string]interface{})
+		if er3 = dynamodbattribute.UnmarshalMap(outputPassword.Item, &raw); er3 != nil {
+			return nil, er3
+		}
+	}
+	if password, ok := raw[r.PasswordName].(string); ok {
+		user.Password = password
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if len(r.HistoryTableName) == 0 || max <= 0 {
+		return passwords, nil
+	}
+	keyCond := expression.Key(r.UserIdName).Equal(expression.Value(id))
+	expr, er0 := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if er0 != nil {
+		return nil, er0
+	}
+	query := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.HistoryTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int64(int64(max)),
+	}
+	output, er1 := r.Db.QueryWithContext(ctx, query)
+	if er1 != nil {
+		return nil, er1
+	}
+	for _, item := range output.Items {
+		raw := make(map[string]interface{})
+		if er2 := dynamodbattribute.UnmarshalMap(item, &raw); er2 != nil {
+			return nil, er2
+		}
+		if password, ok := raw[r.PasswordName].(string); ok {
+			passwords = append(passwords, password)
+		}
+	}
+	return passwords, nil
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	pass := make(map[string]interface{})
+	pass["_id"] = id
+	pass[r.PasswordName] = newPassword
+	if len(r.PasswordChangedTimeName) > 0 {
+		pass[r.PasswordChangedTimeName] = now
+	}
+	if len(r.FailCountName) > 0 {
+		pass[r.FailCountName] = 0
+	}
+	if len(r.LockedUntilTimeName) > 0 {
+		pass[r.LockedUntilTimeName] = nil
+	}
+	_, err := patchOne(ctx, r.Db, r.PasswordTableName, []string{"_id"}, pass)
+	if err != nil {
+		if err.Error() == "object not found" {
+			return 0, nil
+		}
+		return -1, err
+	}
+	if len(r.HistoryTableName) == 0 {
+		return 1, nil
+	}
+	history := map[string]interface{}{r.UserIdName: id, r.PasswordName: newPassword, r.historyTimeName(): now}
+	if _, err = upsertOne(ctx, r.Db, r.HistoryTableName, history); err != nil {
+		return -1, err
+	}
+	return 1, nil
+}
+
+func (r *PasswordRepository) historyTimeName() string {
+	if len(r.PasswordChangedTimeName) > 0 {
+		return r.PasswordChangedTimeName
+	}
+	return "passwordChangedTime"
+}
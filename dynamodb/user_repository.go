@@ -41,10 +41,13 @@ type UserRepository struct {
 	AccessTimeFromName      string
 	AccessTimeToName        string
 	TwoFactorsName          string
+	TenantName              string
 }
 
 func NewUserRepositoryByConfig(dynamoDB *dynamodb.DynamoDB, userTableName, passwordTableName string, activatedStatus interface{}, status auth.UserStatusConfig, c auth.SchemaConfig, options ...func(context.Context, string) (bool, error)) *UserRepository {
-	return NewUserRepository(dynamoDB, userTableName, passwordTableName, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.Roles, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r := NewUserRepository(dynamoDB, userTableName, passwordTableName, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.Roles, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r.TenantName = c.Tenant
+	return r
 }
 func NewUserAdapterByConfig(dynamoDB *dynamodb.DynamoDB, userTableName, passwordTableName string, activatedStatus interface{}, status auth.UserStatusConfig, c auth.SchemaConfig, options ...func(context.Context, string) (bool, error)) *UserRepository {
 	return NewUserRepositoryByConfig(dynamoDB, userTableName, passwordTableName, activatedStatus, status, c, options...)
@@ -87,6 +90,13 @@ func NewUserRepository(dynamoDB *dynamodb.DynamoDB, userTableName, passwordTable
 func (r *UserRepository) GetUser(ctx context.Context, username string) (*auth.UserInfo, error) {
 	userInfo := auth.UserInfo{}
 	filter := expression.Equal(expression.Name("_id"), expression.Value(username))
+	if len(r.TenantName) > 0 {
+		tenant := auth.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		filter = filter.And(expression.Equal(expression.Name(r.TenantName), expression.Value(tenant)))
+	}
 	expr, _ := expression.NewBuilder().WithFilter(filter).Build()
 	query := &dynamodb.ScanInput{
 		TableName:                 aws.String(r.UserTableName),
@@ -335,9 +345,9 @@ func (r *UserRepository) Fail(ctx context.Context, userId string, failCount *int
 	}
 	if len(r.FailCountName) > 0 && failCount != nil {
 		pass[r.FailCountName] = *failCount + 1
-		if len(r.LockedUntilTimeName) > 0 {
-			pass[r.LockedUntilTimeName] = lockedUntil
-		}
+	}
+	if len(r.LockedUntilTimeName) > 0 && lockedUntil != nil {
+		pass[r.LockedUntilTimeName] = lockedUntil
 	}
 	_, err := patchOne(ctx, r.Db, r.PasswordTableName, []string{"_id"}, pass)
 	return err
@@ -0,0 +1,71 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	a "github.com/core-go/authentication"
+	"github.com/labstack/echo/v4"
+)
+
+type PolicyAuthorizer struct {
+	Evaluate   func(req a.AccessRequest) (bool, string)
+	Attributes []string
+	Ip         string
+	LogError   func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewPolicyAuthorizer(evaluate func(req a.AccessRequest) (bool, string), attributes []string, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), opts ...string) *PolicyAuthorizer {
+	var ip string
+	if len(opts) > 0 {
+		ip = opts[0]
+	} else {
+		ip = "ip"
+	}
+	return &PolicyAuthorizer{Evaluate: evaluate, Attributes: attributes, Ip: ip, LogError: logError}
+}
+
+func (h *PolicyAuthorizer) check(ctx context.Context, action string, resourceType string, resource map[string]interface{}) (int, string) {
+	subject := make(map[string]interface{})
+	for _, key := range h.Attributes {
+		if v := ctx.Value(key); v != nil {
+			subject[key] = v
+		}
+	}
+	if len(subject) == 0 {
+		return http.StatusUnauthorized, "invalid subject"
+	}
+	ip, _ := ctx.Value(h.Ip).(string)
+	req := a.AccessRequest{Action: action, ResourceType: resourceType, Subject: subject, Resource: resource, Environment: a.NewEnvironment(ip, time.Now())}
+	if allowed, _ := h.Evaluate(req); !allowed {
+		return http.StatusForbidden, "no permission"
+	}
+	return http.StatusOK, ""
+}
+
+func (h *PolicyAuthorizer) logError(ctx context.Context, err error) {
+	if h.LogError != nil {
+		h.LogError(ctx, err.Error())
+	}
+}
+
+func (h *PolicyAuthorizer) Authorize(action string, resourceType string, resource func(ctx echo.Context) (map[string]interface{}, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			var attributes map[string]interface{}
+			if resource != nil {
+				var err error
+				attributes, err = resource(ctx)
+				if err != nil {
+					h.logError(ctx.Request().Context(), err)
+					return ctx.String(http.StatusInternalServerError, "cannot load resource")
+				}
+			}
+			if code, msg := h.check(ctx.Request().Context(), action, resourceType, attributes); code != http.StatusOK {
+				return ctx.String(code, msg)
+			}
+			return next(ctx)
+		}
+	}
+}
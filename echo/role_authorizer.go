@@ -0,0 +1,49 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+
+	a "github.com/core-go/authentication"
+	"github.com/labstack/echo/v4"
+)
+
+type RoleAuthorizer struct {
+	HasRole func(userRoles []string, roles ...string) bool
+	Roles   string
+}
+
+func NewRoleAuthorizer(hasRole func(userRoles []string, roles ...string) bool, opts ...string) *RoleAuthorizer {
+	var roles string
+	if len(opts) > 0 {
+		roles = opts[0]
+	} else {
+		roles = "roles"
+	}
+	if hasRole == nil {
+		hasRole = a.ContainsRole
+	}
+	return &RoleAuthorizer{HasRole: hasRole, Roles: roles}
+}
+
+func (h *RoleAuthorizer) check(ctx context.Context, roles []string) (int, string) {
+	userRoles := a.ToRoles(ctx.Value(h.Roles))
+	if len(userRoles) == 0 {
+		return http.StatusUnauthorized, "invalid roles"
+	}
+	if !h.HasRole(userRoles, roles...) {
+		return http.StatusForbidden, "no permission"
+	}
+	return http.StatusOK, ""
+}
+
+func (h *RoleAuthorizer) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if code, msg := h.check(ctx.Request().Context(), roles); code != http.StatusOK {
+				return ctx.String(code, msg)
+			}
+			return next(ctx)
+		}
+	}
+}
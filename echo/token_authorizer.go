@@ -0,0 +1,100 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	a "github.com/core-go/authentication"
+	"github.com/labstack/echo/v4"
+)
+
+type TokenAuthorizer struct {
+	Secret         string
+	VerifyToken    func(tokenString string, secret string) (map[string]interface{}, int64, int64, error)
+	CheckBlacklist func(ctx context.Context, id string, token string, createAt time.Time) string
+	CheckWhitelist func(ctx context.Context, id string, token string) bool
+	Id             string
+	Ip             string
+	Tenant         string
+	ResolveTenant  func(r *http.Request) string
+}
+
+func NewTokenAuthorizer(secret string, verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error),
+	checkBlacklist func(ctx context.Context, id string, token string, createAt time.Time) string,
+	opts ...string) *TokenAuthorizer {
+	return NewTokenAuthorizerWithWhitelist(secret, verifyToken, checkBlacklist, nil, opts...)
+}
+func NewTokenAuthorizerWithWhitelist(secret string, verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error),
+	checkBlacklist func(ctx context.Context, id string, token string, createAt time.Time) string,
+	checkWhitelist func(ctx context.Context, id string, token string) bool,
+	opts ...string) *TokenAuthorizer {
+	var id, ip string
+	if len(opts) > 0 {
+		id = opts[0]
+	} else {
+		id = "id"
+	}
+	if len(opts) > 1 {
+		ip = opts[1]
+	} else {
+		ip = "ip"
+	}
+	return &TokenAuthorizer{
+		Secret:         secret,
+		VerifyToken:    verifyToken,
+		CheckBlacklist: checkBlacklist,
+		CheckWhitelist: checkWhitelist,
+		Id:             id,
+		Ip:             ip,
+	}
+}
+
+func (h *TokenAuthorizer) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		r := ctx.Request()
+		token := parseToken(r.Header["Authorization"])
+		if len(token) == 0 {
+			return ctx.String(http.StatusUnauthorized, "invalid authorization token")
+		}
+		payload, iat, _, err := h.VerifyToken(token, h.Secret)
+		if err != nil {
+			return ctx.String(http.StatusUnauthorized, "invalid authorization token")
+		}
+		c := r.Context()
+		id, _ := payload[h.Id].(string)
+		if h.CheckBlacklist != nil {
+			reason := h.CheckBlacklist(c, id, token, time.Unix(iat, 0))
+			if len(reason) > 0 {
+				return ctx.String(http.StatusUnauthorized, reason)
+			}
+		}
+		if h.CheckWhitelist != nil && !h.CheckWhitelist(c, id, token) {
+			return ctx.String(http.StatusUnauthorized, "invalid authorization token")
+		}
+		if len(h.Tenant) > 0 {
+			tenant := a.GetTenant(c)
+			if h.ResolveTenant != nil {
+				tenant = h.ResolveTenant(r)
+			}
+			claim, _ := payload[h.Tenant].(string)
+			if len(claim) == 0 || len(tenant) > 0 && claim != tenant {
+				return ctx.String(http.StatusForbidden, "invalid tenant")
+			}
+			c = a.WithTenant(c, claim)
+		}
+		ip := getForwardedRemoteIp(r)
+		if len(ip) == 0 {
+			ip = getRemoteIp(r)
+		}
+		c = context.WithValue(c, "token", token)
+		c = context.WithValue(c, h.Ip, ip)
+		for k, e := range payload {
+			if len(k) > 0 {
+				c = context.WithValue(c, k, e)
+			}
+		}
+		ctx.SetRequest(r.WithContext(c))
+		return next(ctx)
+	}
+}
@@ -0,0 +1,153 @@
+package echo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	a "github.com/core-go/authentication"
+	"github.com/core-go/authentication/webauthn"
+	"github.com/labstack/echo/v4"
+)
+
+type WebAuthnHandler struct {
+	Service       webauthn.Service
+	SystemError   int
+	GenerateToken func(payload interface{}, secret string, expiresIn int64) (string, error)
+	TokenConfig   a.TokenConfig
+	Tenants       map[string]a.TenantAuthConfig
+	PayloadConfig a.PayloadConfig
+	Error         func(context.Context, string, ...map[string]interface{})
+	Log           func(ctx context.Context, resource string, action string, success bool, desc string) error
+	Ip            string
+	UserId        string
+	Username      string
+	Resource      string
+	Action        string
+}
+
+func NewWebAuthnHandler(service webauthn.Service, systemError int, generateToken func(payload interface{}, secret string, expiresIn int64) (string, error), tokenConfig a.TokenConfig, payloadConfig a.PayloadConfig, logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *WebAuthnHandler {
+	var ip, userId, username, resource, action string
+	if len(options) > 0 {
+		ip = options[0]
+	} else {
+		ip = "ip"
+	}
+	if len(options) > 1 {
+		userId = options[1]
+	} else {
+		userId = "userId"
+	}
+	if len(options) > 2 {
+		username = options[2]
+	} else {
+		username = "username"
+	}
+	if len(options) > 3 {
+		resource = options[3]
+	} else {
+		resource = "authentication"
+	}
+	if len(options) > 4 {
+		action = options[4]
+	} else {
+		action = "webauthn"
+	}
+	return &WebAuthnHandler{Service: service, SystemError: systemError, GenerateToken: generateToken, TokenConfig: tokenConfig, PayloadConfig: payloadConfig, Error: logError, Log: writeLog, Ip: ip, UserId: userId, Username: username, Resource: resource, Action: action}
+}
+
+func (h *WebAuthnHandler) BeginRegistration(ctx echo.Context) error {
+	r := ctx.Request()
+	userId := a.FromContext(r.Context(), h.UserId)
+	if len(userId) == 0 {
+		return ctx.String(http.StatusUnauthorized, "unauthorized")
+	}
+	var req webauthn.RegistrationRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return ctx.String(http.StatusBadRequest, "cannot decode registration request")
+		}
+	}
+	if len(req.Username) == 0 {
+		req.Username = a.FromContext(r.Context(), h.Username)
+	}
+	options, err := h.Service.BeginRegistration(r.Context(), userId, req.Username, req.DisplayName)
+	if err != nil {
+		return h.respondError(ctx, err)
+	}
+	return respond(ctx, http.StatusOK, options, h.Log, h.Resource, h.Action, true, "")
+}
+
+func (h *WebAuthnHandler) FinishRegistration(ctx echo.Context) error {
+	r := ctx.Request()
+	userId := a.FromContext(r.Context(), h.UserId)
+	if len(userId) == 0 {
+		return ctx.String(http.StatusUnauthorized, "unauthorized")
+	}
+	var credential webauthn.RegistrationCredential
+	if err := json.NewDecoder(r.Body).Decode(&credential); err != nil {
+		return ctx.String(http.StatusBadRequest, "cannot decode credential")
+	}
+	c, err := h.Service.FinishRegistration(r.Context(), userId, credential)
+	if err != nil {
+		return h.respondError(ctx, err)
+	}
+	return respond(ctx, http.StatusCreated, c, h.Log, h.Resource, h.Action, true, "")
+}
+
+func (h *WebAuthnHandler) BeginLogin(ctx echo.Context) error {
+	r := ctx.Request()
+	var req webauthn.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Username) == 0 {
+		return ctx.String(http.StatusBadRequest, "cannot decode login request")
+	}
+	options, err := h.Service.BeginLogin(r.Context(), req.Username)
+	if err != nil {
+		return h.respondError(ctx, err)
+	}
+	return respond(ctx, http.StatusOK, options, h.Log, h.Resource, h.Action, true, "")
+}
+
+func (h *WebAuthnHandler) FinishLogin(ctx echo.Context) error {
+	r := ctx.Request()
+	var req webauthn.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Username) == 0 || req.Credential == nil {
+		return ctx.String(http.StatusBadRequest, "cannot decode login request")
+	}
+	c := r.Context()
+	if len(h.Ip) > 0 {
+		c = context.WithValue(c, h.Ip, getRemoteIp(r))
+		ctx.SetRequest(r.WithContext(c))
+	}
+	result, err := h.Service.FinishLogin(c, req.Username, *req.Credential)
+	if err != nil {
+		if h.Error != nil {
+			h.Error(c, err.Error())
+		}
+		result.Status = h.SystemError
+		return respond(ctx, http.StatusInternalServerError, result, h.Log, h.Resource, h.Action, false, err.Error())
+	}
+	if result.User != nil && h.GenerateToken != nil {
+		payload := a.UserAccountToPayload(c, result.User, h.PayloadConfig)
+		token, er1 := h.GenerateToken(payload, h.TokenConfig.Secret, a.TenantExpires(c, h.Tenants, h.TokenConfig.Expires))
+		if er1 != nil {
+			if h.Error != nil {
+				h.Error(c, er1.Error())
+			}
+			return respond(ctx, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.Action, false, er1.Error())
+		}
+		result.Token = token
+	}
+	return respond(ctx, http.StatusOK, result, h.Log, h.Resource, h.Action, result.User != nil, "")
+}
+
+func (h *WebAuthnHandler) respondError(ctx echo.Context, err error) error {
+	if errors.Is(err, webauthn.ErrVerification) {
+		return respond(ctx, http.StatusBadRequest, err.Error(), h.Log, h.Resource, h.Action, false, err.Error())
+	}
+	if h.Error != nil {
+		h.Error(ctx.Request().Context(), err.Error())
+	}
+	return respond(ctx, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.Action, false, err.Error())
+}
@@ -0,0 +1,26 @@
+package echo
+
+import (
+	"net/http"
+
+	"github.com/core-go/authentication/jwt"
+	"github.com/labstack/echo"
+)
+
+type JWKSHandler struct {
+	JWKS   func() jwt.JWKS
+	MaxAge string
+}
+
+func NewJWKSHandler(jwks func() jwt.JWKS, options ...string) *JWKSHandler {
+	maxAge := "300"
+	if len(options) > 0 {
+		maxAge = options[0]
+	}
+	return &JWKSHandler{JWKS: jwks, MaxAge: maxAge}
+}
+
+func (h *JWKSHandler) GetJWKS(ctx echo.Context) error {
+	ctx.Response().Header().Set("Cache-Control", "public, max-age="+h.MaxAge)
+	return ctx.JSON(http.StatusOK, h.JWKS())
+}
@@ -0,0 +1,131 @@
+package echo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	a "github.com/core-go/authentication"
+	"github.com/labstack/echo"
+)
+
+type PasswordHandler struct {
+	Forgot       func(ctx context.Context, contact string) (bool, error)
+	Reset        func(ctx context.Context, pass a.PasswordReset) (int, string, error)
+	Change       func(ctx context.Context, pass a.PasswordChange) (int, error)
+	Error        func(context.Context, string, ...map[string]interface{})
+	Log          func(ctx context.Context, resource string, action string, success bool, desc string) error
+	Resource     string
+	Action       string
+	ResetAction  string
+	ChangeAction string
+	UserId       string
+	Username     string
+	Events       a.AuthEventSink
+}
+
+func NewPasswordHandler(forgot func(context.Context, string) (bool, error), reset func(context.Context, a.PasswordReset) (int, string, error), change func(context.Context, a.PasswordChange) (int, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *PasswordHandler {
+	var resource, action, resetAction, changeAction, userId, username string
+	if len(options) > 0 {
+		resource = options[0]
+	} else {
+		resource = "password"
+	}
+	if len(options) > 1 {
+		action = options[1]
+	} else {
+		action = "forgot"
+	}
+	if len(options) > 2 {
+		resetAction = options[2]
+	} else {
+		resetAction = "reset"
+	}
+	if len(options) > 3 {
+		changeAction = options[3]
+	} else {
+		changeAction = "change"
+	}
+	if len(options) > 4 {
+		userId = options[4]
+	} else {
+		userId = "userId"
+	}
+	if len(options) > 5 {
+		username = options[5]
+	} else {
+		username = "username"
+	}
+	return &PasswordHandler{Forgot: forgot, Reset: reset, Change: change, Error: logError, Log: writeLog, Resource: resource, Action: action, ResetAction: resetAction, ChangeAction: changeAction, UserId: userId, Username: username}
+}
+
+func (h *PasswordHandler) ForgotPassword(ctx echo.Context) error {
+	r := ctx.Request()
+	var pass a.PasswordReset
+	if err := json.NewDecoder(r.Body).Decode(&pass); err != nil || len(pass.Username) == 0 {
+		return ctx.String(http.StatusBadRequest, "cannot decode password reset request")
+	}
+	sent, err := h.Forgot(r.Context(), pass.Username)
+	if err != nil {
+		if h.Error != nil {
+			h.Error(r.Context(), err.Error())
+		}
+		return respond(ctx, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.Action, false, err.Error())
+	}
+	return respond(ctx, http.StatusOK, true, h.Log, h.Resource, h.Action, sent, "")
+}
+
+func (h *PasswordHandler) ResetPassword(ctx echo.Context) error {
+	r := ctx.Request()
+	var pass a.PasswordReset
+	if err := json.NewDecoder(r.Body).Decode(&pass); err != nil || len(pass.Passcode) == 0 || len(pass.Password) == 0 {
+		return ctx.String(http.StatusBadRequest, "cannot decode password reset request")
+	}
+	result, username, err := h.Reset(r.Context(), pass)
+	if err != nil {
+		if violations, ok := err.(a.PasswordViolations); ok {
+			return respond(ctx, http.StatusUnprocessableEntity, violations, h.Log, h.Resource, h.ResetAction, false, err.Error())
+		}
+		if h.Error != nil {
+			h.Error(r.Context(), err.Error())
+		}
+		return respond(ctx, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.ResetAction, false, err.Error())
+	}
+	if result == a.PasswordChanged && h.Events != nil {
+		a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventPasswordReset, Username: username, Ip: getRemoteIp(r), Status: result})
+	}
+	return respond(ctx, http.StatusOK, result, h.Log, h.Resource, h.ResetAction, result == a.PasswordChanged, "")
+}
+
+func (h *PasswordHandler) ChangePassword(ctx echo.Context) error {
+	r := ctx.Request()
+	var pass a.PasswordChange
+	if err := json.NewDecoder(r.Body).Decode(&pass); err != nil || len(pass.CurrentPassword) == 0 || len(pass.Password) == 0 {
+		return ctx.String(http.StatusBadRequest, "cannot decode password change request")
+	}
+	// a signed in user can only change its own password; without a session (e.g. an expired password),
+	// the body username is used and the service verifies the current password and the lockout
+	if userId := a.FromContext(r.Context(), h.UserId); len(userId) > 0 {
+		pass.Id = userId
+		if username := a.FromContext(r.Context(), h.Username); len(username) > 0 {
+			pass.Username = username
+		}
+	}
+	if len(pass.Username) == 0 {
+		return ctx.String(http.StatusBadRequest, "cannot decode password change request")
+	}
+	result, err := h.Change(r.Context(), pass)
+	if err != nil {
+		if violations, ok := err.(a.PasswordViolations); ok {
+			return respond(ctx, http.StatusUnprocessableEntity, violations, h.Log, h.Resource, h.ChangeAction, false, err.Error())
+		}
+		if h.Error != nil {
+			h.Error(r.Context(), err.Error())
+		}
+		return respond(ctx, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.ChangeAction, false, err.Error())
+	}
+	if result == a.PasswordChanged && h.Events != nil {
+		a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventPasswordChanged, Username: pass.Username, Ip: getRemoteIp(r), Status: result})
+	}
+	return respond(ctx, http.StatusOK, result, h.Log, h.Resource, h.ChangeAction, result == a.PasswordChanged, "")
+}
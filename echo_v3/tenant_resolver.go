@@ -0,0 +1,28 @@
+package echo
+
+import (
+	"net/http"
+
+	a "github.com/core-go/authentication"
+	"github.com/labstack/echo"
+)
+
+type TenantResolver struct {
+	Resolve func(r *http.Request) string
+}
+
+func NewTenantResolver(resolve func(r *http.Request) string) *TenantResolver {
+	return &TenantResolver{Resolve: resolve}
+}
+
+func (h *TenantResolver) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		r := ctx.Request()
+		tenant := h.Resolve(r)
+		if len(tenant) == 0 {
+			return ctx.String(http.StatusBadRequest, "invalid tenant")
+		}
+		ctx.SetRequest(r.WithContext(a.WithTenant(r.Context(), tenant)))
+		return next(ctx)
+	}
+}
@@ -0,0 +1,218 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	auth "github.com/core-go/authentication"
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/elastic/go-elasticsearch/v7/esutil"
+)
+
+type PasswordRepository struct {
+	Client                  *elasticsearch.Client
+	UserIndexName           string
+	PasswordIndexName       string
+	HistoryIndexName        string
+	UserName                string
+	UserIdName              string
+	PasswordName            string
+	PasswordChangedTimeName string
+	FailCountName           string
+	LockedUntilTimeName     string
+	ContactName             string
+	EmailName               string
+	DisplayNameName         string
+	LanguageName            string
+}
+
+func NewPasswordRepositoryByConfig(client *elasticsearch.Client, userIndexName, passwordIndexName, historyIndexName string, c auth.SchemaConfig) *PasswordRepository {
+	if len(passwordIndexName) == 0 {
+		passwordIndexName = userIndexName
+	}
+	userName := c.Username
+	if len(userName) == 0 {
+		userName = "username"
+	}
+	userIdName := c.UserId
+	if len(userIdName) == 0 {
+		userIdName = "userId"
+	}
+	passwordName := c.Password
+	if len(passwordName) == 0 {
+		passwordName = "password"
+	}
+	return &PasswordRepository{Client: client, UserIndexName: userIndexName, PasswordIndexName: passwordIndexName, HistoryIndexName: historyIndexName, UserName: userName, UserIdName: userIdName, PasswordName: passwordName, PasswordChangedTimeName: c.PasswordChangedTime, FailCountName: c.FailCount, LockedUntilTimeName: c.LockedUntilTime, ContactName: c.Contact, EmailName: c.Email, DisplayNameName: c.DisplayName, LanguageName: c.Language}
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*auth.UserInfo, error) {
+	should := []interface{}{map[string]interface{}{"term": map[string]interface{}{r.UserName: contact}}}
+	if len(r.EmailName) > 0 {
+		should = append(should, map[string]interface{}{"term": map[string]interface{}{r.EmailName: contact}})
+	}
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		},
+	}
+	hits, err := search(ctx, r.Client, r.UserIndexName, query)
+	if err != nil || len(hits) == 0 {
+		return nil, err
+	}
+	user := auth.UserInfo{}
+	if id, ok := hits[0]["_id"].(string); ok {
+		user.Id = id
+	}
+	raw, _ := hits[0]["_source"].(map[string]interface{})
+	if username, ok := raw[r.UserName].(string); ok {
+		user.Username = username
+	}
+	if len(r.EmailName) > 0 {
+		if email, ok := raw[r.EmailName].(string); ok {
+			user.Email = &email
+		}
+	}
+	if len(r.ContactName) > 0 {
+		if contact, ok := raw[r.ContactName].(string); ok {
+			user.Contact = &contact
+		}
+	}
+	if len(r.DisplayNameName) > 0 {
+		if displayName, ok := raw[r.DisplayNameName].(string); ok {
+			user.DisplayName = &displayName
+		}
+	}
+	if len(r.LanguageName) > 0 {
+		if language, ok := raw[r.LanguageName].(string); ok {
+			user.Language = &language
+		}
+	}
+	if r.UserIndexName != r.PasswordIndexName {
+		query = map[string]interface{}{
+			"size":  1,
+			"query": map[string]interface{}{"ids": map[string]interface{}{"values": []string{user.Id}}},
+		}
+		hits, err = search(ctx, r.Client, r.PasswordIndexName, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(hits) == 0 {
+			return &user, nil
+		}
+		raw, _ = hits[0]["_source"].(map[string]interface{})
+	}
+	if password, ok := raw[r.PasswordName].(string); ok {
+		user.Password = password
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if len(r.HistoryIndexName) == 0 || max <= 0 {
+		return passwords, nil
+	}
+	query := map[string]interface{}{
+		"size":  max,
+		"query": map[string]interface{}{"term": map[string]interface{}{r.UserIdName: id}},
+		"sort":  []interface{}{map[string]interface{}{r.historyTimeName(): map[string]interface{}{"order": "desc"}}},
+	}
+	hits, err := search(ctx, r.Client, r.HistoryIndexName, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		raw, _ := hit["_source"].(map[string]interface{})
+		if password, ok := raw[r.PasswordName].(string); ok {
+			passwords = append(passwords, password)
+		}
+	}
+	return passwords, nil
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	pass := map[string]interface{}{r.PasswordName: newPassword}
+	if len(r.PasswordChangedTimeName) > 0 {
+		pass[r.PasswordChangedTimeName] = now
+	}
+	if len(r.FailCountName) > 0 {
+		pass[r.FailCountName] = 0
+	}
+	if len(r.LockedUntilTimeName) > 0 {
+		pass[r.LockedUntilTimeName] = nil
+	}
+	req := esapi.UpdateRequest{
+		Index:      r.PasswordIndexName,
+		DocumentID: id,
+		Body:       esutil.NewJSONReader(map[string]interface{}{"doc": pass}),
+		Refresh:    "true",
+	}
+	res, err := req.Do(ctx, r.Client)
+	if err != nil {
+		return -1, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if res.IsError() {
+		return -1, errors.New("response error")
+	}
+	if len(r.HistoryIndexName) > 0 {
+		history := map[string]interface{}{r.UserIdName: id, r.PasswordName: newPassword, r.historyTimeName(): now}
+		insert := esapi.IndexRequest{
+			Index:   r.HistoryIndexName,
+			Body:    esutil.NewJSONReader(history),
+			Refresh: "true",
+		}
+		resHistory, er1 := insert.Do(ctx, r.Client)
+		if er1 != nil {
+			return -1, er1
+		}
+		defer resHistory.Body.Close()
+		if resHistory.IsError() {
+			return -1, errors.New("response error")
+		}
+	}
+	return 1, nil
+}
+
+func (r *PasswordRepository) historyTimeName() string {
+	if len(r.PasswordChangedTimeName) > 0 {
+		return r.PasswordChangedTimeName
+	}
+	return "passwordChangedTime"
+}
+
+func search(ctx context.Context, es *elasticsearch.Client, index string, query map[string]interface{}) ([]map[string]interface{}, error) {
+	req := esapi.SearchRequest{
+		Index: []string{index},
+		Body:  esutil.NewJSONReader(query),
+	}
+	res, err := req.Do(ctx, es)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.New("response error")
+	}
+	var r struct {
+		Hits struct {
+			Hits []map[string]interface{} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	return r.Hits.Hits, nil
+}
@@ -40,10 +40,13 @@ type AuthenticationRepository struct {
 	AccessTimeFromName      string
 	AccessTimeToName        string
 	TwoFactorsName          string
+	TenantName              string
 }
 
 func NewAuthenticationRepositoryByConfig(client *elasticsearch.Client, userIndexName, passwordIndexName string, activatedStatus interface{}, status auth.UserStatusConfig, c auth.SchemaConfig, options ...func(context.Context, string) (bool, error)) *AuthenticationRepository {
-	return NewAuthenticationRepository(client, userIndexName, passwordIndexName, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.Roles, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r := NewAuthenticationRepository(client, userIndexName, passwordIndexName, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.Roles, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r.TenantName = c.Tenant
+	return r
 }
 
 func NewAuthenticationRepository(client *elasticsearch.Client, userIndexName, passwordIndexName string, activatedStatus interface{}, status auth.UserStatusConfig, userName, successTimeName, failTimeName, failCountName, lockedUntilTimeName, statusName, passwordChangedTimeName, passwordName, contactName, emailName, phoneName, displayNameName, maxPasswordAgeName, rolesName, userTypeName, accessDateFromName, accessDateToName, accessTimeFromName, accessTimeToName string, twoFactorsName string, options ...func(context.Context, string) (bool, error)) *AuthenticationRepository {
@@ -65,6 +68,20 @@ func (r *AuthenticationRepository) GetUserInfo(ctx context.Context, username str
 			},
 		},
 	}
+	if len(r.TenantName) > 0 {
+		tenant := auth.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		query["query"] = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"match": map[string]interface{}{"_id": username}},
+					{"term": map[string]interface{}{r.TenantName: tenant}},
+				},
+			},
+		}
+	}
 	raw := make(map[string]interface{})
 	ok, err := findOneAndDecode(ctx, r.Client, []string{r.UserIndexName}, query, &raw)
 	if !ok || err != nil {
@@ -276,7 +293,7 @@ func (r *AuthenticationRepository) passAuthenticationAndActivate(ctx context.Con
 	return k1 + k2, er2
 }
 
-func (r *AuthenticationRepository) Fail(ctx context.Context, userId string, failCount int, lockedUntil *time.Time) error {
+func (r *AuthenticationRepository) Fail(ctx context.Context, userId string, failCount *int, lockedUntil *time.Time) error {
 	if len(r.FailTimeName) == 0 && len(r.FailCountName) == 0 && len(r.LockedUntilTimeName) == 0 {
 		return nil
 	}
@@ -285,11 +302,11 @@ func (r *AuthenticationRepository) Fail(ctx context.Context, userId string, fail
 	if len(r.FailTimeName) > 0 {
 		pass[r.FailTimeName] = time.Now()
 	}
-	if len(r.FailCountName) > 0 {
-		pass[r.FailCountName] = failCount
-		if len(r.LockedUntilTimeName) > 0 {
-			pass[r.LockedUntilTimeName] = lockedUntil
-		}
+	if len(r.FailCountName) > 0 && failCount != nil {
+		pass[r.FailCountName] = *failCount + 1
+	}
+	if len(r.LockedUntilTimeName) > 0 && lockedUntil != nil {
+		pass[r.LockedUntilTimeName] = lockedUntil
 	}
 	_, err := upsertOne(ctx, r.Client, r.PasswordIndexName, userId, pass)
 	return err
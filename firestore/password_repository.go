@@ -0,0 +1,191 @@
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	a "github.com/core-go/authentication"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type PasswordRepository struct {
+	UserCollection          *firestore.CollectionRef
+	PasswordCollection      *firestore.CollectionRef
+	HistoryCollection       *firestore.CollectionRef
+	UserName                string
+	UserIdName              string
+	PasswordName            string
+	PasswordChangedTimeName string
+	FailCountName           string
+	LockedUntilTimeName     string
+	ContactName             string
+	EmailName               string
+	DisplayNameName         string
+	LanguageName            string
+}
+
+func NewPasswordRepositoryByConfig(client *firestore.Client, userCollectionName, passwordCollectionName, historyCollectionName string, c a.SchemaConfig) *PasswordRepository {
+	if len(passwordCollectionName) == 0 {
+		passwordCollectionName = userCollectionName
+	}
+	passwordCollection := client.Collection(passwordCollectionName)
+	userCollection := passwordCollection
+	if passwordCollectionName != userCollectionName {
+		userCollection = client.Collection(userCollectionName)
+	}
+	var historyCollection *firestore.CollectionRef
+	if len(historyCollectionName) > 0 {
+		historyCollection = client.Collection(historyCollectionName)
+	}
+	userName := c.Username
+	if len(userName) == 0 {
+		userName = "username"
+	}
+	userIdName := c.UserId
+	if len(userIdName) == 0 {
+		userIdName = "userId"
+	}
+	passwordName := c.Password
+	if len(passwordName) == 0 {
+		passwordName = "password"
+	}
+	return &PasswordRepository{
+		UserCollection:          userCollection,
+		PasswordCollection:      passwordCollection,
+		HistoryCollection:       historyCollection,
+		UserName:                userName,
+		UserIdName:              userIdName,
+		PasswordName:            passwordName,
+		PasswordChangedTimeName: c.PasswordChangedTime,
+		FailCountName:           c.FailCount,
+		LockedUntilTimeName:     c.LockedUntilTime,
+		ContactName:             c.Contact,
+		EmailName:               c.Email,
+		DisplayNameName:         c.DisplayName,
+		LanguageName:            c.Language,
+	}
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*a.UserInfo, error) {
+	doc, err := r.findUser(ctx, r.UserName, contact)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil && len(r.EmailName) > 0 {
+		doc, err = r.findUser(ctx, r.EmailName, contact)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	raw := doc.Data()
+	user := a.UserInfo{Id: doc.Ref.ID}
+	if username, ok := raw[r.UserName].(string); ok {
+		user.Username = username
+	}
+	if len(r.EmailName) > 0 {
+		if email, ok := raw[r.EmailName].(string); ok {
+			user.Email = &email
+		}
+	}
+	if len(r.ContactName) > 0 {
+		if contact, ok := raw[r.ContactName].(string); ok {
+			user.Contact = &contact
+		}
+	}
+	if len(r.DisplayNameName) > 0 {
+		if displayName, ok := raw[r.DisplayNameName].(string); ok {
+			user.DisplayName = &displayName
+		}
+	}
+	if len(r.LanguageName) > 0 {
+		if language, ok := raw[r.LanguageName].(string); ok {
+			user.Language = &language
+		}
+	}
+	if r.UserCollection.ID != r.PasswordCollection.ID {
+		passwordDoc, er1 := r.PasswordCollection.Doc(user.Id).Get(ctx)
+		if er1 != nil {
+			if status.Code(er1) == codes.NotFound {
+				return &user, nil
+			}
+			return nil, er1
+		}
+		raw = passwordDoc.Data()
+	}
+	if password, ok := raw[r.PasswordName].(string); ok {
+		user.Password = password
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) findUser(ctx context.Context, field string, value string) (*firestore.DocumentSnapshot, error) {
+	iter := r.UserCollection.Where(field, "==", value).Limit(1).Documents(ctx)
+	defer iter.Stop()
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, nil
+	}
+	return doc, err
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if r.HistoryCollection == nil || max <= 0 {
+		return passwords, nil
+	}
+	iter := r.HistoryCollection.Where(r.UserIdName, "==", id).OrderBy(r.historyTimeName(), firestore.Desc).Limit(max).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if password, ok := doc.Data()[r.PasswordName].(string); ok {
+			passwords = append(passwords, password)
+		}
+	}
+	return passwords, nil
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	updates := []firestore.Update{{Path: r.PasswordName, Value: newPassword}}
+	if len(r.PasswordChangedTimeName) > 0 {
+		updates = append(updates, firestore.Update{Path: r.PasswordChangedTimeName, Value: now})
+	}
+	if len(r.FailCountName) > 0 {
+		updates = append(updates, firestore.Update{Path: r.FailCountName, Value: 0})
+	}
+	if len(r.LockedUntilTimeName) > 0 {
+		updates = append(updates, firestore.Update{Path: r.LockedUntilTimeName, Value: nil})
+	}
+	_, err := r.PasswordCollection.Doc(id).Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, nil
+		}
+		return -1, err
+	}
+	if r.HistoryCollection != nil {
+		history := map[string]interface{}{r.UserIdName: id, r.PasswordName: newPassword, r.historyTimeName(): now}
+		if _, _, err = r.HistoryCollection.Add(ctx, history); err != nil {
+			return -1, err
+		}
+	}
+	return 1, nil
+}
+
+func (r *PasswordRepository) historyTimeName() string {
+	if len(r.PasswordChangedTimeName) > 0 {
+		return r.PasswordChangedTimeName
+	}
+	return "passwordChangedTime"
+}
@@ -37,10 +37,13 @@ type UserRepository struct {
 	AccessTimeFromName      string
 	AccessTimeToName        string
 	TwoFactorsName          string
+	TenantName              string
 }
 
 func NewUserRepositoryByConfig(client *firestore.Client, userCollectionName, passwordCollectionName string, checkTwoFactors func(ctx context.Context, id string) (bool, error), activatedStatus interface{}, status a.UserStatusConfig, c a.SchemaConfig) *UserRepository {
-	return NewUserRepository(client, userCollectionName, passwordCollectionName, checkTwoFactors, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.Roles, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors)
+	r := NewUserRepository(client, userCollectionName, passwordCollectionName, checkTwoFactors, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.Roles, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors)
+	r.TenantName = c.Tenant
+	return r
 }
 
 func NewUserRepository(client *firestore.Client, userCollectionName, passwordCollectionName string, checkTwoFactors func(ctx context.Context, id string) (bool, error), activatedStatus interface{}, status a.UserStatusConfig, userName, successTimeName, failTimeName, failCountName, lockedUntilTimeName, statusName, roleName, passwordChangedTimeName, passwordName, contactName, emailName, phoneName, displayNameName, maxPasswordAgeName, userTypeName, accessDateFromName, accessDateToName, accessTimeFromName, accessTimeToName, twoFactorsName string) *UserRepository {
@@ -81,7 +84,15 @@ func NewUserRepository(client *firestore.Client, userCollectionName, passwordCol
 func (r *UserRepository) GetUser(ctx context.Context, username string) (*a.UserInfo, error) {
 	userInfo := a.UserInfo{}
 	//query := bson.M{"_id": id}
-	iter := r.UserCollection.Where(r.UserName, "==", username).Documents(ctx)
+	q := r.UserCollection.Where(r.UserName, "==", username)
+	if len(r.TenantName) > 0 {
+		tenant := a.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		q = q.Where(r.TenantName, "==", tenant)
+	}
+	iter := q.Documents(ctx)
 	defer iter.Stop()
 	result, err := iter.Next()
 	if err == iterator.Done {
@@ -410,9 +421,9 @@ func (r *UserRepository) Fail(ctx context.Context, userId string, failCount *int
 	}
 	if failCount != nil && len(r.FailCountName) > 0 {
 		pass[r.FailCountName] = *failCount + 1
-		if len(r.LockedUntilTimeName) > 0 {
-			pass[r.LockedUntilTimeName] = lockedUntil
-		}
+	}
+	if len(r.LockedUntilTimeName) > 0 && lockedUntil != nil {
+		pass[r.LockedUntilTimeName] = lockedUntil
 	}
 	//query := bson.M{"_id": userId}
 	_, err := r.upsertWithMap(ctx, r.PasswordCollection, userId, pass)
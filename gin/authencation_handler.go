@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	a "github.com/core-go/authentication"
+	"github.com/core-go/authentication/random"
 	"github.com/gin-gonic/gin"
 	"net"
 	"net/http"
@@ -33,6 +34,7 @@ type AuthenticationHandler struct {
 	UserId             string
 	Whitelist          func(id string, token string) error
 	IpFromRequest      bool
+	TrustedProxies     []string
 	Log                func(ctx context.Context, resource string, action string, success bool, desc string) error
 	Resource           string
 	Action             string
@@ -54,6 +56,11 @@ type AuthenticationHandler struct {
 	EncodeSessionID func(sid string) string
 
 	Decrypt func(string) (string, error)
+
+	RateLimit       func(ctx context.Context, ip string, info a.AuthInfo) (bool, time.Duration, error)
+	TooManyRequests int
+	Events          a.AuthEventSink
+	Sessions        *a.SessionRegistry
 }
 type LogError func(context.Context, string, ...map[string]interface{})
 type Authenticate func(context.Context, a.AuthInfo) (a.AuthResult, error)
@@ -153,6 +160,9 @@ func NewAuthenticationHandlerWithCache(authenticate Authenticate, systemError in
 	} else {
 		logoutAction = "logout"
 	}
+	if generate == nil {
+		generate = random.SessionId
+	}
 	return &AuthenticationHandler{
 		Auth:               authenticate,
 		Resource:           resource,
@@ -253,6 +263,26 @@ func (h *AuthenticationHandler) Authenticate(ctx2 *gin.Context) {
 		ctx = context.WithValue(ctx, h.Ip, ip)
 		r = r.WithContext(ctx)
 	}
+	if h.RateLimit != nil {
+		// never key on the body's ip, a client could rotate it to get a fresh bucket on every request
+		ip := a.ClientIp(r, h.TrustedProxies)
+		allowed, retryAfter, er0 := h.RateLimit(r.Context(), ip, user)
+		if er0 != nil {
+			if h.Error != nil {
+				h.Error(r.Context(), er0.Error())
+			}
+			respond(ctx2, http.StatusInternalServerError, a.AuthResult{Status: h.SystemError}, h.Log, h.Resource, h.Action, false, er0.Error())
+			return
+		}
+		if !allowed {
+			if h.Events != nil {
+				a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventRateLimited, Username: user.Username, Ip: ip, Device: user.Device, Status: h.TooManyRequests, Reason: "too many requests"})
+			}
+			ctx2.Header("Retry-After", retryAfterSeconds(retryAfter))
+			respond(ctx2, http.StatusTooManyRequests, a.AuthResult{Status: h.TooManyRequests}, h.Log, h.Resource, h.Action, false, "too many requests")
+			return
+		}
+	}
 
 	if h.Decrypt != nil {
 		if decodedPassword, er2 := h.Decrypt(user.Password); er2 != nil {
@@ -317,6 +347,7 @@ func (h *AuthenticationHandler) Authenticate(ctx2 *gin.Context) {
 				r = r.WithContext(ctx)
 			}
 			if h.Store != nil && h.Generate != nil && len(h.Host) > 0 {
+				h.invalidateSession(r)
 				if h.SingleSession {
 					indexData := make(map[string]interface{})
 					data1, _ := h.Store.Get(r.Context(), h.PrefixSessionIndex+result.User.Id)
@@ -339,6 +370,7 @@ func (h *AuthenticationHandler) Authenticate(ctx2 *gin.Context) {
 				session := make(map[string]string)
 				session["token"] = token
 				session[h.Id] = result.User.Id
+				session[a.SessionRotatedAt] = time.Now().Format(time.RFC3339)
 				host := r.Header.Get("Origin")
 				if strings.Contains(host, h.Host) || strings.Contains(host, "localhost") {
 					u, err := url.Parse(host)
@@ -372,6 +404,15 @@ func (h *AuthenticationHandler) Authenticate(ctx2 *gin.Context) {
 					respond(ctx2, http.StatusInternalServerError, nil, h.Log, h.Resource, h.Action, false, err2.Error())
 					return
 				}
+				if h.Sessions != nil {
+					now := time.Now()
+					_, err3 := h.Sessions.Add(r.Context(), a.SessionInfo{Id: sessionId, UserId: result.User.Id, Ip: ip, UserAgent: r.UserAgent(), CreatedAt: now, LastSeen: now})
+					if err3 != nil {
+						h.Error(r.Context(), err3.Error())
+						respond(ctx2, http.StatusInternalServerError, nil, h.Log, h.Resource, h.Action, false, err3.Error())
+						return
+					}
+				}
 				if h.EncodeSessionID != nil {
 					sessionId = h.EncodeSessionID(sessionId)
 				}
@@ -425,7 +466,7 @@ func (h *AuthenticationHandler) Logout(ctx2 *gin.Context) {
 	}
 	data, err := GetCookie(ctx2.Request.Context(), valueCookie, h.SId, h.Store.Get)
 	if err != nil {
-		if err.Error() == "redis: nil" {
+		if a.IsCacheMiss(err) {
 			respond(ctx2, http.StatusOK, 1, h.Log, h.Resource, h.LogoutAction, true, err.Error())
 			return
 		}
@@ -450,14 +491,41 @@ func (h *AuthenticationHandler) Logout(ctx2 *gin.Context) {
 	}
 	userId := GetString(data, h.Id)
 	if len(userId) > 0 {
+		if h.Sessions != nil && len(sessionId) > 0 {
+			_, err = h.Sessions.Revoke(ctx2.Request.Context(), userId, sessionId)
+			if err != nil {
+				respond(ctx2, http.StatusInternalServerError, "", h.Log, h.Resource, h.LogoutAction, false, err.Error())
+				return
+			}
+		}
 		_, err = h.Store.Remove(ctx2.Request.Context(), h.PrefixSessionIndex+userId)
 		if err != nil {
 			respond(ctx2, http.StatusInternalServerError, "", h.Log, h.Resource, h.LogoutAction, false, err.Error())
 			return
 		}
 	}
+	if h.Events != nil {
+		a.WriteEvent(ctx2.Request.Context(), h.Events, a.AuthEvent{Type: a.EventLogout, UserId: userId, Ip: getRemoteIp(ctx2.Request)})
+	}
 	respond(ctx2, http.StatusOK, 1, h.Log, h.Resource, h.LogoutAction, true, "")
 }
+
+// invalidateSession removes the session the client had before logging in, so that an id planted before authentication cannot be reused.
+func (h *AuthenticationHandler) invalidateSession(r *http.Request) {
+	cookie, err := r.Cookie(h.CookieName)
+	if err != nil || len(cookie.Value) == 0 {
+		return
+	}
+	sessionId := cookie.Value
+	if h.DecodeSessionID != nil {
+		if sessionId, err = h.DecodeSessionID(sessionId); err != nil {
+			return
+		}
+	}
+	if _, err = h.Store.Remove(r.Context(), sessionId); err != nil && h.Error != nil {
+		h.Error(r.Context(), err.Error())
+	}
+}
 func GetCookie(ctx context.Context, value string, sid string, cache func(context.Context, string) (string, error)) (map[string]interface{}, error) {
 	var data map[string]interface{}
 	s, err := cache(ctx, value)
@@ -531,3 +599,11 @@ func respond(ctx *gin.Context, code int, result interface{}, writeLog func(conte
 		writeLog(ctx.Request.Context(), resource, action, success, desc)
 	}
 }
+
+func retryAfterSeconds(retryAfter time.Duration) string {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
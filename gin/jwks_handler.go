@@ -0,0 +1,26 @@
+package gin
+
+import (
+	"net/http"
+
+	"github.com/core-go/authentication/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	JWKS   func() jwt.JWKS
+	MaxAge string
+}
+
+func NewJWKSHandler(jwks func() jwt.JWKS, options ...string) *JWKSHandler {
+	maxAge := "300"
+	if len(options) > 0 {
+		maxAge = options[0]
+	}
+	return &JWKSHandler{JWKS: jwks, MaxAge: maxAge}
+}
+
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age="+h.MaxAge)
+	ctx.JSON(http.StatusOK, h.JWKS())
+}
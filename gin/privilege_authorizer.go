@@ -0,0 +1,58 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	a "github.com/core-go/authentication"
+	"github.com/gin-gonic/gin"
+)
+
+type PrivilegeAuthorizer struct {
+	Privileges func(ctx context.Context, id string) ([]a.Privilege, error)
+	UserId     string
+	LogError   func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewPrivilegeAuthorizer(loadPrivileges func(ctx context.Context, id string) ([]a.Privilege, error), ttl time.Duration, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), opts ...string) *PrivilegeAuthorizer {
+	var userId string
+	if len(opts) > 0 {
+		userId = opts[0]
+	} else {
+		userId = "userId"
+	}
+	if ttl > 0 {
+		loadPrivileges = a.NewPrivilegesCache(loadPrivileges, ttl).Load
+	}
+	return &PrivilegeAuthorizer{Privileges: loadPrivileges, UserId: userId, LogError: logError}
+}
+
+func (h *PrivilegeAuthorizer) check(ctx context.Context, resource string, action int32) (int, string) {
+	userId, _ := ctx.Value(h.UserId).(string)
+	if len(userId) == 0 {
+		return http.StatusUnauthorized, "invalid user id"
+	}
+	privileges, err := h.Privileges(ctx, userId)
+	if err != nil {
+		if h.LogError != nil {
+			h.LogError(ctx, err.Error())
+		}
+		return http.StatusInternalServerError, "cannot load privileges"
+	}
+	if !a.HasPermission(privileges, resource, action) {
+		return http.StatusForbidden, "no permission"
+	}
+	return http.StatusOK, ""
+}
+
+func (h *PrivilegeAuthorizer) Authorize(resource string, action int32) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if code, msg := h.check(ctx.Request.Context(), resource, action); code != http.StatusOK {
+			ctx.String(code, msg)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,123 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	a "github.com/core-go/authentication"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	Sessions *a.SessionRegistry
+	Error    func(context.Context, string, ...map[string]interface{})
+	Log      func(ctx context.Context, resource string, action string, success bool, desc string) error
+	UserId   string
+	SId      string
+	Resource string
+	Offset   int
+	Events   a.AuthEventSink
+}
+
+func NewSessionHandler(sessions *a.SessionRegistry, logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *SessionHandler {
+	var userId, sid, resource string
+	if len(options) > 0 {
+		userId = options[0]
+	} else {
+		userId = "userId"
+	}
+	if len(options) > 1 {
+		sid = options[1]
+	} else {
+		sid = "sid"
+	}
+	if len(options) > 2 {
+		resource = options[2]
+	} else {
+		resource = "session"
+	}
+	return &SessionHandler{Sessions: sessions, Error: logError, Log: writeLog, UserId: userId, SId: sid, Resource: resource}
+}
+
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	r := c.Request
+	ctx := r.Context()
+	userId := a.FromContext(ctx, h.UserId)
+	if len(userId) == 0 {
+		c.String(http.StatusUnauthorized, "invalid user id")
+		return
+	}
+	sessions, err := h.Sessions.View(ctx, userId, a.FromContext(ctx, h.SId))
+	if err != nil {
+		if h.Error != nil {
+			h.Error(ctx, err.Error())
+		}
+		respond(c, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, "list", false, err.Error())
+		return
+	}
+	respond(c, http.StatusOK, sessions, h.Log, h.Resource, "list", true, "")
+}
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	r := c.Request
+	ctx := r.Context()
+	userId := a.FromContext(ctx, h.UserId)
+	if len(userId) == 0 {
+		c.String(http.StatusUnauthorized, "invalid user id")
+		return
+	}
+	handle := h.getSessionId(r.URL.Path)
+	if len(handle) == 0 {
+		c.String(http.StatusBadRequest, "session id is required")
+		return
+	}
+	ok, err := h.Sessions.RevokeHandle(ctx, userId, handle)
+	if err != nil {
+		if h.Error != nil {
+			h.Error(ctx, err.Error())
+		}
+		respond(c, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, "revoke", false, err.Error())
+		return
+	}
+	if !ok {
+		respond(c, http.StatusNotFound, 0, h.Log, h.Resource, "revoke", false, "session not found")
+		return
+	}
+	if h.Events != nil {
+		a.WriteEvent(ctx, h.Events, a.AuthEvent{Type: a.EventLogout, UserId: userId, Reason: "session revoked"})
+	}
+	respond(c, http.StatusOK, 1, h.Log, h.Resource, "revoke", true, "")
+}
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	r := c.Request
+	ctx := r.Context()
+	userId := a.FromContext(ctx, h.UserId)
+	if len(userId) == 0 {
+		c.String(http.StatusUnauthorized, "invalid user id")
+		return
+	}
+	count, err := h.Sessions.RevokeOthers(ctx, userId, a.FromContext(ctx, h.SId))
+	if err != nil {
+		if h.Error != nil {
+			h.Error(ctx, err.Error())
+		}
+		respond(c, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, "revoke_others", false, err.Error())
+		return
+	}
+	respond(c, http.StatusOK, count, h.Log, h.Resource, "revoke_others", true, "")
+}
+
+func (h *SessionHandler) getSessionId(path string) string {
+	if h.Offset <= 0 {
+		i := strings.LastIndex(path, "/")
+		if i >= 0 {
+			return path[i+1:]
+		}
+		return ""
+	}
+	s := strings.Split(path, "/")
+	if len(s)-h.Offset-1 >= 0 {
+		return s[len(s)-h.Offset-1]
+	}
+	return ""
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	a "github.com/core-go/authentication"
 )
 
 type SignOutHandler struct {
@@ -19,6 +21,7 @@ type SignOutHandler struct {
 	Cookie      bool
 	CookieName  string
 	CookieDomain string
+	Events      a.AuthEventSink
 }
 
 func NewSignOutHandler(verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error), secret string, revokeToken func(ctx context.Context, token string, reason string, expires time.Time) error, logError func(context.Context, string, ...map[string]interface{}), options...func(context.Context, string, string, bool, string) error) *SignOutHandler {
@@ -64,6 +67,9 @@ func (h *SignOutHandler) SignOutCookie(ctx *gin.Context) {
 		}
 		http.SetCookie(ctx.Writer, cookie)
 	}
+	if h.Events != nil {
+		a.WriteEvent(ctx.Request.Context(), h.Events, a.AuthEvent{Type: a.EventLogout, Ip: getRemoteIp(ctx.Request)})
+	}
 	respond(ctx, http.StatusOK, true, h.Log, h.Resource, h.Action, true, "")
 }
 func (h *SignOutHandler) SignOut(ctx *gin.Context) {
@@ -104,6 +110,9 @@ func (h *SignOutHandler) SignOut(ctx *gin.Context) {
 		ctx.String(http.StatusInternalServerError, internalServerError)
 		return
 	}
+	if h.Events != nil {
+		a.WriteEvent(ctx.Request.Context(), h.Events, a.AuthEvent{Type: a.EventTokenRevoked, Ip: getRemoteIp(ctx.Request), Reason: "The token has signed out."})
+	}
 	if h.Log != nil {
 		h.Log(ctx.Request.Context(), h.Resource, h.Action, true, "")
 	}
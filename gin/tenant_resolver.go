@@ -0,0 +1,27 @@
+package gin
+
+import (
+	"net/http"
+
+	a "github.com/core-go/authentication"
+	"github.com/gin-gonic/gin"
+)
+
+type TenantResolver struct {
+	Resolve func(r *http.Request) string
+}
+
+func NewTenantResolver(resolve func(r *http.Request) string) *TenantResolver {
+	return &TenantResolver{Resolve: resolve}
+}
+
+func (h *TenantResolver) Handle(ctx *gin.Context) {
+	tenant := h.Resolve(ctx.Request)
+	if len(tenant) == 0 {
+		ctx.String(http.StatusBadRequest, "invalid tenant")
+		ctx.Abort()
+		return
+	}
+	ctx.Request = ctx.Request.WithContext(a.WithTenant(ctx.Request.Context(), tenant))
+	ctx.Next()
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	a "github.com/core-go/authentication"
+	"github.com/core-go/authentication/random"
 )
 
 const expired = "Token is expired"
@@ -29,6 +30,7 @@ type AuthenticationHandler struct {
 	Timeout             int
 	GenerateToken       func(payload interface{}, secret string, expiresIn int64) (string, error)
 	TokenConfig         a.TokenConfig
+	Tenants             map[string]a.TenantAuthConfig
 	RememberTokenConfig a.TokenConfig
 	PayloadConfig       a.PayloadConfig
 	Error               func(context.Context, string, ...map[string]interface{})
@@ -36,6 +38,7 @@ type AuthenticationHandler struct {
 	UserId              string
 	Whitelist           func(id string, token string) error
 	IpFromRequest       bool
+	TrustedProxies      []string
 	Log                 func(ctx context.Context, resource string, action string, success bool, desc string) error
 	Resource            string
 	Action              string
@@ -58,6 +61,17 @@ type AuthenticationHandler struct {
 	EncodeSessionID func(sid string) string
 
 	Decrypt func(string) (string, error)
+
+	RateLimit       func(ctx context.Context, ip string, info a.AuthInfo) (bool, time.Duration, error)
+	TooManyRequests int
+	Events          a.AuthEventSink
+	Sessions        *a.SessionRegistry
+
+	IssueRefreshToken func(ctx context.Context, userId string, payload map[string]interface{}) (string, error)
+
+	// tokens are only issued for these statuses; both default to 1, as in auth.InitStatus
+	Success               int
+	SuccessAndReactivated int
 }
 type LogError func(context.Context, string, ...map[string]interface{})
 type Authenticate func(context.Context, a.AuthInfo) (a.AuthResult, error)
@@ -108,7 +122,7 @@ func NewAuthenticationHandlerWithDecrypter(authenticate Authenticate,
 	} else {
 		action = "authenticate"
 	}
-	return &AuthenticationHandler{Auth: authenticate, SystemError: systemError, Timeout: timeout, SameSite: sameSite, Cookie: cookie, CookieName: cookieName, RememberCookieName: rememberCookieName, Resource: resource, Action: action, GenerateToken: generateToken, TokenConfig: tokenConfig, RememberTokenConfig: rememberTokenConfig, PayloadConfig: payloadConfig, Error: logError, Ip: ip, UserId: userId, Whitelist: addTokenIntoWhitelist, Log: writeLog, Decrypt: decrypt, IpFromRequest: ipFromRequest}
+	return &AuthenticationHandler{Auth: authenticate, SystemError: systemError, Timeout: timeout, Success: 1, SuccessAndReactivated: 1, SameSite: sameSite, Cookie: cookie, CookieName: cookieName, RememberCookieName: rememberCookieName, Resource: resource, Action: action, GenerateToken: generateToken, TokenConfig: tokenConfig, RememberTokenConfig: rememberTokenConfig, PayloadConfig: payloadConfig, Error: logError, Ip: ip, UserId: userId, Whitelist: addTokenIntoWhitelist, Log: writeLog, Decrypt: decrypt, IpFromRequest: ipFromRequest}
 }
 func NewAuthenticationHandlerWithCache(authenticate Authenticate, systemError int, timeout int, logError LogError,
 	store StoreService,
@@ -171,6 +185,9 @@ func NewAuthenticationHandlerWithCache(authenticate Authenticate, systemError in
 	} else {
 		logoutAction = "logout"
 	}
+	if generate == nil {
+		generate = random.SessionId
+	}
 	return &AuthenticationHandler{
 		Auth:               authenticate,
 		Resource:           resource,
@@ -192,6 +209,9 @@ func NewAuthenticationHandlerWithCache(authenticate Authenticate, systemError in
 		Expired:            expired,
 		Host:               host,
 		LogoutAction:       logoutAction,
+
+		Success:               1,
+		SuccessAndReactivated: 1,
 	}
 }
 
@@ -282,6 +302,26 @@ func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Requ
 		ctx = context.WithValue(ctx, h.Ip, ip)
 		r = r.WithContext(ctx)
 	}
+	if h.RateLimit != nil {
+		// never key on the body's ip, a client could rotate it to get a fresh bucket on every request
+		ip := a.ClientIp(r, h.TrustedProxies)
+		allowed, retryAfter, er0 := h.RateLimit(r.Context(), ip, user)
+		if er0 != nil {
+			if h.Error != nil {
+				h.Error(r.Context(), er0.Error())
+			}
+			respond(w, r, http.StatusInternalServerError, a.AuthResult{Status: h.SystemError}, h.Log, h.Resource, h.Action, false, er0.Error())
+			return
+		}
+		if !allowed {
+			if h.Events != nil {
+				a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventRateLimited, Username: user.Username, Ip: ip, Device: user.Device, Status: h.TooManyRequests, Reason: "too many requests"})
+			}
+			w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+			respond(w, r, http.StatusTooManyRequests, a.AuthResult{Status: h.TooManyRequests}, h.Log, h.Resource, h.Action, false, "too many requests")
+			return
+		}
+	}
 
 	if h.Decrypt != nil {
 		if decodedPassword, er2 := h.Decrypt(user.Password); er2 != nil {
@@ -307,12 +347,17 @@ func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Requ
 			respond(w, r, http.StatusInternalServerError, result, h.Log, h.Resource, h.Action, false, er3.Error())
 		}
 	} else {
-		if len(h.UserId) > 0 && result.User != nil && len(result.User.Id) > 0 {
+		// wrong password, locked, expired password and two-factor results carry no signed in user, so no token is issued
+		if result.Status != h.Success && result.Status != h.SuccessAndReactivated || result.User == nil || len(result.User.Id) == 0 {
+			respond(w, r, http.StatusOK, result, h.Log, h.Resource, h.Action, false, "")
+			return
+		}
+		if len(h.UserId) > 0 {
 			ctx = context.WithValue(ctx, h.UserId, result.User.Id)
 			r = r.WithContext(ctx)
 		}
 		payload := a.UserAccountToPayload(ctx, result.User, h.PayloadConfig)
-		token, er4 := h.GenerateToken(payload, h.TokenConfig.Secret, h.TokenConfig.Expires)
+		token, er4 := h.GenerateToken(payload, h.TokenConfig.Secret, a.TenantExpires(r.Context(), h.Tenants, h.TokenConfig.Expires))
 		if er4 != nil {
 			h.Error(r.Context(), er4.Error())
 			respond(w, r, http.StatusInternalServerError, nil, h.Log, h.Resource, h.Action, false, er4.Error())
@@ -321,7 +366,13 @@ func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Requ
 		if h.Whitelist != nil {
 			h.Whitelist(result.User.Id, token)
 		}
-		rememberToken, er5 := h.GenerateToken(payload, h.RememberTokenConfig.Secret, h.RememberTokenConfig.Expires)
+		var rememberToken string
+		var er5 error
+		if h.IssueRefreshToken != nil {
+			rememberToken, er5 = h.IssueRefreshToken(r.Context(), result.User.Id, payload)
+		} else {
+			rememberToken, er5 = h.GenerateToken(payload, h.RememberTokenConfig.Secret, h.RememberTokenConfig.Expires)
+		}
 		if er5 != nil {
 			h.Error(r.Context(), er5.Error())
 			respond(w, r, http.StatusInternalServerError, nil, h.Log, h.Resource, h.Action, false, er5.Error())
@@ -348,6 +399,7 @@ func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Requ
 				r = r.WithContext(ctx)
 			}
 			if h.Store != nil && h.Generate != nil && len(h.Host) > 0 {
+				h.invalidateSession(r)
 				if h.SingleSession {
 					indexData := make(map[string]interface{})
 					data1, _ := h.Store.Get(r.Context(), h.PrefixSessionIndex+result.User.Id)
@@ -370,6 +422,7 @@ func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Requ
 				session := make(map[string]string)
 				session["token"] = token
 				session[h.Id] = result.User.Id
+				session[a.SessionRotatedAt] = time.Now().Format(time.RFC3339)
 				host := r.Header.Get("Origin")
 				if strings.Contains(host, h.Host) || strings.Contains(host, "localhost") {
 					u, err := url.Parse(host)
@@ -403,6 +456,15 @@ func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Requ
 					respond(w, r, http.StatusInternalServerError, nil, h.Log, h.Resource, h.Action, false, err2.Error())
 					return
 				}
+				if h.Sessions != nil {
+					now := time.Now()
+					_, err3 := h.Sessions.Add(r.Context(), a.SessionInfo{Id: sessionId, UserId: result.User.Id, Ip: ip, UserAgent: r.UserAgent(), CreatedAt: now, LastSeen: now})
+					if err3 != nil {
+						h.Error(r.Context(), err3.Error())
+						respond(w, r, http.StatusInternalServerError, nil, h.Log, h.Resource, h.Action, false, err3.Error())
+						return
+					}
+				}
 				if h.EncodeSessionID != nil {
 					sessionId = h.EncodeSessionID(sessionId)
 				}
@@ -476,7 +538,7 @@ func (h *AuthenticationHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 	data, err := GetCookie(r.Context(), valueCookie, h.SId, h.Store.Get)
 	if err != nil {
-		if err.Error() == "redis: nil" {
+		if a.IsCacheMiss(err) {
 			respond(w, r, http.StatusOK, 1, h.Log, h.Resource, h.LogoutAction, true, err.Error())
 			return
 		}
@@ -501,14 +563,41 @@ func (h *AuthenticationHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 	userId := GetString(data, h.Id)
 	if len(userId) > 0 {
+		if h.Sessions != nil && len(sessionId) > 0 {
+			_, err = h.Sessions.Revoke(r.Context(), userId, sessionId)
+			if err != nil {
+				respond(w, r, http.StatusInternalServerError, "", h.Log, h.Resource, h.LogoutAction, false, err.Error())
+				return
+			}
+		}
 		_, err = h.Store.Remove(r.Context(), h.PrefixSessionIndex+userId)
 		if err != nil {
 			respond(w, r, http.StatusInternalServerError, "", h.Log, h.Resource, h.LogoutAction, false, err.Error())
 			return
 		}
 	}
+	if h.Events != nil {
+		a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventLogout, UserId: userId, Ip: getRemoteIp(r)})
+	}
 	respond(w, r, http.StatusOK, 1, h.Log, h.Resource, h.LogoutAction, true, "")
 }
+
+// invalidateSession removes the session the client had before logging in, so that an id planted before authentication cannot be reused.
+func (h *AuthenticationHandler) invalidateSession(r *http.Request) {
+	cookie, err := r.Cookie(h.CookieName)
+	if err != nil || len(cookie.Value) == 0 {
+		return
+	}
+	sessionId := cookie.Value
+	if h.DecodeSessionID != nil {
+		if sessionId, err = h.DecodeSessionID(sessionId); err != nil {
+			return
+		}
+	}
+	if _, err = h.Store.Remove(r.Context(), sessionId); err != nil && h.Error != nil {
+		h.Error(r.Context(), err.Error())
+	}
+}
 func GetCookie(ctx context.Context, value string, sid string, cache func(context.Context, string) (string, error)) (map[string]interface{}, error) {
 	var data map[string]interface{}
 	s, err := cache(ctx, value)
@@ -576,3 +665,11 @@ func GetString(data map[string]interface{}, key string) string {
 	}
 	return ""
 }
+
+func retryAfterSeconds(retryAfter time.Duration) string {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
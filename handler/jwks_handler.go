@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/core-go/authentication/jwt"
+)
+
+type JWKSHandler struct {
+	JWKS   func() jwt.JWKS
+	MaxAge string
+}
+
+func NewJWKSHandler(jwks func() jwt.JWKS, options ...string) *JWKSHandler {
+	maxAge := "300"
+	if len(options) > 0 {
+		maxAge = options[0]
+	}
+	return &JWKSHandler{JWKS: jwks, MaxAge: maxAge}
+}
+
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age="+h.MaxAge)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.JWKS())
+}
+
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.GetJWKS(w, r)
+}
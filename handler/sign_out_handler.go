@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	a "github.com/core-go/authentication"
 )
 
 type SignOutHandler struct {
@@ -18,6 +20,7 @@ type SignOutHandler struct {
 	Cookie      bool
 	CookieName  string
 	CookieDomain string
+	Events      a.AuthEventSink
 }
 
 func NewSignOutHandler(verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error), secret string, revokeToken func(ctx context.Context, token string, reason string, expires time.Time) error, logError func(context.Context, string, ...map[string]interface{}), options...func(context.Context, string, string, bool, string) error) *SignOutHandler {
@@ -63,6 +66,9 @@ func (h *SignOutHandler) SignOutCookie(w http.ResponseWriter, r *http.Request) {
 		}
 		http.SetCookie(w, cookie)
 	}
+	if h.Events != nil {
+		a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventLogout, Ip: getRemoteIp(r)})
+	}
 	respond(w, r, http.StatusOK, true, h.Log, h.Resource, h.Action, true, "")
 }
 func (h *SignOutHandler) SignOut(w http.ResponseWriter, r *http.Request) {
@@ -103,6 +109,9 @@ func (h *SignOutHandler) SignOut(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, internalServerError, http.StatusInternalServerError)
 		return
 	}
+	if h.Events != nil {
+		a.WriteEvent(r.Context(), h.Events, a.AuthEvent{Type: a.EventTokenRevoked, Ip: getRemoteIp(r), Reason: "The token has signed out."})
+	}
 	if h.Log != nil {
 		h.Log(r.Context(), h.Resource, h.Action, true, "")
 	}
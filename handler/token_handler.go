@@ -16,15 +16,21 @@ type TokenHandler struct {
 	CookieName          string
 	RememberCookieName  string
 	TokenConfig         a.TokenConfig
+	Tenants             map[string]a.TenantAuthConfig
 	RememberTokenConfig a.TokenConfig
 	Error               func(context.Context, string, ...map[string]interface{})
 	GenerateToken       func(payload interface{}, secret string, expiresIn int64) (string, error)
 	GetAndVerifyToken   func(authorization string, secret string) (bool, string, map[string]interface{}, int64, int64, error)
 	Resource            string
 	Log                 func(ctx context.Context, resource string, action string, success bool, desc string) error
+	Rotate              func(ctx context.Context, refreshToken string) (string, map[string]interface{}, error)
 }
 
 func (h *TokenHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	if h.Rotate != nil {
+		h.RotateToken(w, r)
+		return
+	}
 	if h.GetAndVerifyToken == nil {
 		http.Error(w, "refresh token is not supported", http.StatusNotImplemented)
 		return
@@ -45,7 +51,7 @@ func (h *TokenHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newToken, err := h.GenerateToken(data, h.TokenConfig.Secret, h.TokenConfig.Expires)
+	newToken, err := h.GenerateToken(data, h.TokenConfig.Secret, a.TenantExpires(r.Context(), h.Tenants, h.TokenConfig.Expires))
 	if err != nil {
 		if h.Error != nil {
 			h.Error(r.Context(), err.Error())
@@ -54,14 +60,49 @@ func (h *TokenHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	host := r.Header.Get("Origin")
-	if strings.Contains(host, h.Host) || strings.Contains(host, "localhost") {
-		u, parseErr := url.Parse(host)
-		if parseErr == nil {
-			host = strings.TrimPrefix(u.Hostname(), "www.")
+	host := h.getHost(r)
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.CookieName,
+		Domain:   host,
+		Value:    newToken,
+		HttpOnly: true,
+		Path:     "/",
+		MaxAge:   0,
+		Expires:  time.Now().Add(time.Duration(a.TenantExpires(r.Context(), h.Tenants, h.TokenConfig.Expires)) * time.Millisecond),
+		SameSite: h.SameSite,
+		Secure:   true,
+	})
+
+	respond(w, r, http.StatusOK, 1, h.Log, h.Resource, "refresh_token", true, "")
+}
+
+func (h *TokenHandler) RotateToken(w http.ResponseWriter, r *http.Request) {
+	rememberCookie, err := r.Cookie(h.RememberCookieName)
+	if err != nil || rememberCookie == nil || len(rememberCookie.Value) == 0 {
+		http.Error(w, h.RememberCookieName+" is required in cookies", http.StatusUnauthorized)
+		return
+	}
+	refreshToken, data, err := h.Rotate(r.Context(), rememberCookie.Value)
+	if err != nil {
+		if err == a.ErrRefreshTokenInvalid || err == a.ErrRefreshTokenRevoked || err == a.ErrRefreshTokenReused {
+			respond(w, r, http.StatusUnauthorized, err.Error(), h.Log, h.Resource, "refresh_token", false, err.Error())
+			return
 		}
+		if h.Error != nil {
+			h.Error(r.Context(), err.Error())
+		}
+		http.Error(w, "failed to rotate refresh token", http.StatusInternalServerError)
+		return
 	}
-
+	newToken, err := h.GenerateToken(data, h.TokenConfig.Secret, a.TenantExpires(r.Context(), h.Tenants, h.TokenConfig.Expires))
+	if err != nil {
+		if h.Error != nil {
+			h.Error(r.Context(), err.Error())
+		}
+		http.Error(w, "failed to generate token", http.StatusInternalServerError)
+		return
+	}
+	host := h.getHost(r)
 	http.SetCookie(w, &http.Cookie{
 		Name:     h.CookieName,
 		Domain:   host,
@@ -69,10 +110,31 @@ func (h *TokenHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		HttpOnly: true,
 		Path:     "/",
 		MaxAge:   0,
-		Expires:  time.Now().Add(time.Duration(h.TokenConfig.Expires) * time.Millisecond),
+		Expires:  time.Now().Add(time.Duration(a.TenantExpires(r.Context(), h.Tenants, h.TokenConfig.Expires)) * time.Millisecond),
+		SameSite: h.SameSite,
+		Secure:   true,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.RememberCookieName,
+		Domain:   host,
+		Value:    refreshToken,
+		HttpOnly: true,
+		Path:     "/",
+		MaxAge:   0,
+		Expires:  time.Now().Add(time.Duration(h.RememberTokenConfig.Expires) * time.Millisecond),
 		SameSite: h.SameSite,
 		Secure:   true,
 	})
-
 	respond(w, r, http.StatusOK, 1, h.Log, h.Resource, "refresh_token", true, "")
 }
+
+func (h *TokenHandler) getHost(r *http.Request) string {
+	host := r.Header.Get("Origin")
+	if strings.Contains(host, h.Host) || strings.Contains(host, "localhost") {
+		u, parseErr := url.Parse(host)
+		if parseErr == nil {
+			host = strings.TrimPrefix(u.Hostname(), "www.")
+		}
+	}
+	return host
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	a "github.com/core-go/authentication"
+	"github.com/core-go/authentication/webauthn"
+)
+
+type WebAuthnHandler struct {
+	Service       webauthn.Service
+	SystemError   int
+	GenerateToken func(payload interface{}, secret string, expiresIn int64) (string, error)
+	TokenConfig   a.TokenConfig
+	Tenants       map[string]a.TenantAuthConfig
+	PayloadConfig a.PayloadConfig
+	Error         func(context.Context, string, ...map[string]interface{})
+	Log           func(ctx context.Context, resource string, action string, success bool, desc string) error
+	Ip            string
+	UserId        string
+	Username      string
+	Resource      string
+	Action        string
+}
+
+func NewWebAuthnHandler(service webauthn.Service, systemError int, generateToken func(payload interface{}, secret string, expiresIn int64) (string, error), tokenConfig a.TokenConfig, payloadConfig a.PayloadConfig, logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *WebAuthnHandler {
+	var ip, userId, username, resource, action string
+	if len(options) > 0 {
+		ip = options[0]
+	} else {
+		ip = "ip"
+	}
+	if len(options) > 1 {
+		userId = options[1]
+	} else {
+		userId = "userId"
+	}
+	if len(options) > 2 {
+		username = options[2]
+	} else {
+		username = "username"
+	}
+	if len(options) > 3 {
+		resource = options[3]
+	} else {
+		resource = "authentication"
+	}
+	if len(options) > 4 {
+		action = options[4]
+	} else {
+		action = "webauthn"
+	}
+	return &WebAuthnHandler{Service: service, SystemError: systemError, GenerateToken: generateToken, TokenConfig: tokenConfig, PayloadConfig: payloadConfig, Error: logError, Log: writeLog, Ip: ip, UserId: userId, Username: username, Resource: resource, Action: action}
+}
+
+func (h *WebAuthnHandler) BeginRegistration(w http.ResponseWriter, r *http.Request) {
+	userId := a.FromContext(r.Context(), h.UserId)
+	if len(userId) == 0 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req webauthn.RegistrationRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "cannot decode registration request", http.StatusBadRequest)
+			return
+		}
+	}
+	if len(req.Username) == 0 {
+		req.Username = a.FromContext(r.Context(), h.Username)
+	}
+	options, err := h.Service.BeginRegistration(r.Context(), userId, req.Username, req.DisplayName)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	respond(w, r, http.StatusOK, options, h.Log, h.Resource, h.Action, true, "")
+}
+
+func (h *WebAuthnHandler) FinishRegistration(w http.ResponseWriter, r *http.Request) {
+	userId := a.FromContext(r.Context(), h.UserId)
+	if len(userId) == 0 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var credential webauthn.RegistrationCredential
+	if err := json.NewDecoder(r.Body).Decode(&credential); err != nil {
+		http.Error(w, "cannot decode credential", http.StatusBadRequest)
+		return
+	}
+	c, err := h.Service.FinishRegistration(r.Context(), userId, credential)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	respond(w, r, http.StatusCreated, c, h.Log, h.Resource, h.Action, true, "")
+}
+
+func (h *WebAuthnHandler) BeginLogin(w http.ResponseWriter, r *http.Request) {
+	var req webauthn.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Username) == 0 {
+		http.Error(w, "cannot decode login request", http.StatusBadRequest)
+		return
+	}
+	options, err := h.Service.BeginLogin(r.Context(), req.Username)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	respond(w, r, http.StatusOK, options, h.Log, h.Resource, h.Action, true, "")
+}
+
+func (h *WebAuthnHandler) FinishLogin(w http.ResponseWriter, r *http.Request) {
+	var req webauthn.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Username) == 0 || req.Credential == nil {
+		http.Error(w, "cannot decode login request", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	if len(h.Ip) > 0 {
+		ctx = context.WithValue(ctx, h.Ip, getRemoteIp(r))
+		r = r.WithContext(ctx)
+	}
+	result, err := h.Service.FinishLogin(ctx, req.Username, *req.Credential)
+	if err != nil {
+		if h.Error != nil {
+			h.Error(ctx, err.Error())
+		}
+		result.Status = h.SystemError
+		respond(w, r, http.StatusInternalServerError, result, h.Log, h.Resource, h.Action, false, err.Error())
+		return
+	}
+	if result.User != nil && h.GenerateToken != nil {
+		payload := a.UserAccountToPayload(ctx, result.User, h.PayloadConfig)
+		token, er1 := h.GenerateToken(payload, h.TokenConfig.Secret, a.TenantExpires(ctx, h.Tenants, h.TokenConfig.Expires))
+		if er1 != nil {
+			if h.Error != nil {
+				h.Error(ctx, er1.Error())
+			}
+			respond(w, r, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.Action, false, er1.Error())
+			return
+		}
+		result.Token = token
+	}
+	respond(w, r, http.StatusOK, result, h.Log, h.Resource, h.Action, result.User != nil, "")
+}
+
+func (h *WebAuthnHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, webauthn.ErrVerification) {
+		respond(w, r, http.StatusBadRequest, err.Error(), h.Log, h.Resource, h.Action, false, err.Error())
+		return
+	}
+	if h.Error != nil {
+		h.Error(r.Context(), err.Error())
+	}
+	respond(w, r, http.StatusInternalServerError, internalServerError, h.Log, h.Resource, h.Action, false, err.Error())
+}
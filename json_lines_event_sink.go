@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+type JSONLinesEventSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewJSONLinesEventSink(path string) *JSONLinesEventSink {
+	return &JSONLinesEventSink{Path: path}
+}
+
+func (s *JSONLinesEventSink) Write(ctx context.Context, event AuthEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(line)
+	if er1 := f.Close(); err == nil {
+		err = er1
+	}
+	return err
+}
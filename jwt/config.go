@@ -0,0 +1,8 @@
+package jwt
+
+type Config struct {
+	Issuer   string   `yaml:"issuer" mapstructure:"issuer" json:"issuer,omitempty" gorm:"column:issuer" bson:"issuer,omitempty" dynamodbav:"issuer,omitempty" firestore:"issuer,omitempty"`
+	Audience []string `yaml:"audience" mapstructure:"audience" json:"audience,omitempty" gorm:"column:audience" bson:"audience,omitempty" dynamodbav:"audience,omitempty" firestore:"audience,omitempty"`
+	Subject  string   `yaml:"subject" mapstructure:"subject" json:"subject,omitempty" gorm:"column:subject" bson:"subject,omitempty" dynamodbav:"subject,omitempty" firestore:"subject,omitempty"`
+	Leeway   int64    `yaml:"leeway" mapstructure:"leeway" json:"leeway,omitempty" gorm:"column:leeway" bson:"leeway,omitempty" dynamodbav:"leeway,omitempty" firestore:"leeway,omitempty"`
+}
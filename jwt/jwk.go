@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func ToJWKS(keys []*Key) JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		if jwk, err := ToJWK(key); err == nil {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+func ToJWK(key *Key) (JWK, error) {
+	jwk := JWK{Kid: key.Id, Use: "sig", Alg: key.Algorithm}
+	switch k := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(k.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		k.X.FillBytes(x)
+		k.Y.FillBytes(y)
+		jwk.X = encode(x)
+		jwk.Y = encode(y)
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encode(k)
+	default:
+		return jwk, ErrUnsupportedAlgorithm
+	}
+	return jwk, nil
+}
+
+func FromJWK(jwk JWK) (*Key, error) {
+	key := &Key{Id: jwk.Kid, Algorithm: jwk.Alg}
+	switch jwk.Kty {
+	case "RSA":
+		n, er1 := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, er2 := base64.RawURLEncoding.DecodeString(jwk.E)
+		if er1 != nil || er2 != nil {
+			return nil, errors.New("invalid RSA key " + jwk.Kid)
+		}
+		key.PublicKey = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if len(key.Algorithm) == 0 {
+			key.Algorithm = RS256
+		}
+	case "EC":
+		x, er1 := base64.RawURLEncoding.DecodeString(jwk.X)
+		y, er2 := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if er1 != nil || er2 != nil || jwk.Crv != "P-256" {
+			return nil, errors.New("invalid EC key " + jwk.Kid)
+		}
+		key.PublicKey = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if len(key.Algorithm) == 0 {
+			key.Algorithm = ES256
+		}
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || jwk.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid OKP key " + jwk.Kid)
+		}
+		key.PublicKey = ed25519.PublicKey(x)
+		if len(key.Algorithm) == 0 {
+			key.Algorithm = EdDSA
+		}
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if err := checkKeyType(key.Algorithm, key.PublicKey); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/core-go/authentication/random"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+type Key struct {
+	Id         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+	Secret     []byte
+	CreatedAt  time.Time
+	RetiredAt  *time.Time
+	ExpiresAt  *time.Time
+}
+
+func NewKey(id string, algorithm string, privateKey crypto.Signer) (*Key, error) {
+	if privateKey == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+	if err := checkKeyType(algorithm, privateKey.Public()); err != nil {
+		return nil, err
+	}
+	if len(id) == 0 {
+		kid, err := newKeyId()
+		if err != nil {
+			return nil, err
+		}
+		id = kid
+	}
+	return &Key{Id: id, Algorithm: algorithm, PrivateKey: privateKey, PublicKey: privateKey.Public(), CreatedAt: time.Now()}, nil
+}
+
+func NewHMACKey(id string, secret string) *Key {
+	return &Key{Id: id, Algorithm: HS256, Secret: []byte(secret), CreatedAt: time.Now()}
+}
+
+func GenerateKey(algorithm string) (*Key, error) {
+	var privateKey crypto.Signer
+	var err error
+	switch algorithm {
+	case RS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case ES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case EdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewKey("", algorithm, privateKey)
+}
+
+func ParsePrivateKey(id string, algorithm string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM private key")
+	}
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+	return NewKey(id, algorithm, signer)
+}
+
+func (k *Key) canVerify(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+func checkKeyType(algorithm string, publicKey crypto.PublicKey) error {
+	switch algorithm {
+	case RS256:
+		if _, ok := publicKey.(*rsa.PublicKey); ok {
+			return nil
+		}
+	case ES256:
+		if k, ok := publicKey.(*ecdsa.PublicKey); ok && k.Curve == elliptic.P256() {
+			return nil
+		}
+	case EdDSA:
+		if _, ok := publicKey.(ed25519.PublicKey); ok {
+			return nil
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+	return errors.New("key type does not match algorithm " + algorithm)
+}
+
+func newKeyId() (string, error) {
+	return random.Token(12)
+}
@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+type KeyManager struct {
+	Algorithm        string
+	RotationInterval time.Duration
+	GracePeriod      time.Duration
+	Generate         func(algorithm string) (*Key, error)
+	mu               sync.RWMutex
+	rotating         sync.Mutex
+	keys             []*Key
+}
+
+func NewKeyManager(algorithm string, rotationInterval time.Duration, gracePeriod time.Duration, keys ...*Key) (*KeyManager, error) {
+	if algorithm != RS256 && algorithm != ES256 && algorithm != EdDSA {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	m := &KeyManager{Algorithm: algorithm, RotationInterval: rotationInterval, GracePeriod: gracePeriod, Generate: GenerateKey}
+	for _, key := range keys {
+		if key == nil {
+			return nil, errors.New("key cannot be nil")
+		}
+		m.keys = append(m.keys, key)
+	}
+	if len(m.keys) == 0 {
+		if _, err := m.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *KeyManager) Current() (*Key, error) {
+	if current, due := m.due(); !due {
+		return current, nil
+	}
+	// only one caller rotates; the others wait and use the key it published
+	m.rotating.Lock()
+	defer m.rotating.Unlock()
+	if current, due := m.due(); !due {
+		return current, nil
+	}
+	return m.Rotate()
+}
+
+func (m *KeyManager) due() (*Key, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	current := m.current()
+	return current, current == nil || m.RotationInterval > 0 && time.Since(current.CreatedAt) >= m.RotationInterval
+}
+
+func (m *KeyManager) Rotate() (*Key, error) {
+	key, err := m.Generate(m.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	m.Add(key)
+	return key, nil
+}
+
+func (m *KeyManager) Add(key *Key) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if current := m.current(); current != nil {
+		expiresAt := now.Add(m.GracePeriod)
+		current.RetiredAt = &now
+		current.ExpiresAt = &expiresAt
+	}
+	keys := make([]*Key, 0, len(m.keys)+1)
+	for _, k := range m.keys {
+		if k.canVerify(now) {
+			keys = append(keys, k)
+		}
+	}
+	m.keys = append(keys, key)
+}
+
+func (m *KeyManager) Resolve(kid string) (*Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	now := time.Now()
+	for _, k := range m.keys {
+		if k.Id == kid && k.canVerify(now) {
+			return k, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+func (m *KeyManager) Keys() []*Key {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	now := time.Now()
+	keys := make([]*Key, 0, len(m.keys))
+	for _, k := range m.keys {
+		if k.canVerify(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (m *KeyManager) JWKS() JWKS {
+	return ToJWKS(m.Keys())
+}
+
+func (m *KeyManager) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := m.Current(); err != nil {
+					log.Println(err)
+				}
+			}
+		}
+	}()
+}
+
+func (m *KeyManager) GenerateToken(payload interface{}, secret string, expiresIn int64) (string, error) {
+	key, err := m.Current()
+	if err != nil {
+		return "", err
+	}
+	return Sign(key, payload, expiresIn)
+}
+
+func (m *KeyManager) VerifyToken(tokenString string, secret string) (map[string]interface{}, int64, int64, error) {
+	return Parse(tokenString, m.Resolve)
+}
+
+func (m *KeyManager) current() *Key {
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if m.keys[i].RetiredAt == nil && m.keys[i].PrivateKey != nil {
+			return m.keys[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type RemoteKeySet struct {
+	URL         string
+	Client      *http.Client
+	RefreshTime time.Duration
+	mu          sync.Mutex
+	keys        map[string]*Key
+	fetchedAt   time.Time
+	fetching    chan struct{}
+	fetchErr    error
+}
+
+func NewRemoteKeySet(url string, options ...time.Duration) *RemoteKeySet {
+	refreshTime := 5 * time.Minute
+	if len(options) > 0 && options[0] > 0 {
+		refreshTime = options[0]
+	}
+	return &RemoteKeySet{URL: url, Client: &http.Client{Timeout: 10 * time.Second}, RefreshTime: refreshTime}
+}
+
+func (s *RemoteKeySet) Resolve(kid string) (*Key, error) {
+	s.mu.Lock()
+	if key, ok := s.keys[kid]; ok && time.Since(s.fetchedAt) < s.RefreshTime {
+		s.mu.Unlock()
+		return key, nil
+	}
+	if s.keys != nil && time.Since(s.fetchedAt) < time.Second {
+		s.mu.Unlock()
+		return nil, ErrUnknownKey
+	}
+	// only one request fetches; the others wait for it instead of queuing on the lock behind the HTTP call
+	done := s.fetching
+	if done == nil {
+		done = make(chan struct{})
+		s.fetching = done
+		s.mu.Unlock()
+		keys, err := s.fetch(context.Background())
+		s.mu.Lock()
+		if err == nil {
+			s.keys = keys
+			s.fetchedAt = time.Now()
+		}
+		s.fetchErr = err
+		s.fetching = nil
+		close(done)
+	} else {
+		s.mu.Unlock()
+		<-done
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if s.fetchErr != nil {
+		return nil, s.fetchErr
+	}
+	return nil, ErrUnknownKey
+}
+
+func (s *RemoteKeySet) VerifyToken(tokenString string, secret string) (map[string]interface{}, int64, int64, error) {
+	return Parse(tokenString, s.Resolve)
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]*Key, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("cannot fetch JWKS: " + res.Status)
+	}
+	var jwks JWKS
+	if err = json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*Key)
+	for _, jwk := range jwks.Keys {
+		if key, er1 := FromJWK(jwk); er1 == nil {
+			keys[key.Id] = key
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,248 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrTokenExpired         = errors.New("Token is expired")
+	ErrInvalidSignature     = error(invalidError("invalid token signature"))
+	ErrUnknownKey           = error(invalidError("unknown signing key"))
+	ErrTokenNotValidYet     = error(invalidError("token is not valid yet"))
+	ErrInvalidIssuer        = error(invalidError("invalid token issuer"))
+	ErrInvalidAudience      = error(invalidError("invalid token audience"))
+)
+
+type invalidError string
+
+func (e invalidError) Error() string {
+	return string(e)
+}
+func (e invalidError) Is(target error) bool {
+	return target == ErrInvalidToken
+}
+
+func IsExpired(err error) bool {
+	return errors.Is(err, ErrTokenExpired)
+}
+func IsInvalid(err error) bool {
+	return errors.Is(err, ErrInvalidToken)
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyId     string `json:"kid,omitempty"`
+}
+
+func Sign(key *Key, payload interface{}, expiresIn int64) (string, error) {
+	claims, err := toClaims(payload)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	if expiresIn > 0 {
+		claims["exp"] = now.Add(time.Duration(expiresIn) * time.Millisecond).Unix()
+	}
+	return SignClaims(key, claims)
+}
+
+func SignClaims(key *Key, claims map[string]interface{}) (string, error) {
+	h, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyId: key.Id})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := encode(h) + "." + encode(c)
+	signature, err := signature(key, []byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encode(signature), nil
+}
+
+func Parse(tokenString string, resolve func(kid string) (*Key, error)) (map[string]interface{}, int64, int64, error) {
+	claims, err := ParseClaims(tokenString, resolve)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	iat := toInt64(claims["iat"])
+	exp := toInt64(claims["exp"])
+	return claims, iat, exp, ValidateTime(claims, time.Now(), 0)
+}
+
+func ParseClaims(tokenString string, resolve func(kid string) (*Key, error)) (map[string]interface{}, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, err := resolve(h.KeyId)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	if key.Algorithm != h.Algorithm {
+		return nil, ErrInvalidSignature
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !verify(key, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, ErrInvalidSignature
+	}
+	claims := make(map[string]interface{})
+	if err = decodeJSON(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func ValidateTime(claims map[string]interface{}, now time.Time, leeway time.Duration) error {
+	if exp := toInt64(claims["exp"]); exp > 0 && !now.Add(-leeway).Before(time.Unix(exp, 0)) {
+		return ErrTokenExpired
+	}
+	if nbf := toInt64(claims["nbf"]); nbf > 0 && now.Add(leeway).Before(time.Unix(nbf, 0)) {
+		return ErrTokenNotValidYet
+	}
+	return nil
+}
+
+func signature(key *Key, input []byte) ([]byte, error) {
+	if key.Algorithm == HS256 {
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	}
+	if key.PrivateKey == nil {
+		return nil, errors.New("key " + key.Id + " cannot sign")
+	}
+	switch key.Algorithm {
+	case RS256:
+		hashed := sha256.Sum256(input)
+		return key.PrivateKey.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	case ES256:
+		privateKey, ok := key.PrivateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		hashed := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, hashed[:])
+		if err != nil {
+			return nil, err
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	case EdDSA:
+		return key.PrivateKey.Sign(rand.Reader, input, crypto.Hash(0))
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+func verify(key *Key, input []byte, sig []byte) bool {
+	switch key.Algorithm {
+	case HS256:
+		if len(key.Secret) == 0 {
+			return false
+		}
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(input)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case RS256:
+		publicKey, ok := key.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return false
+		}
+		hashed := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], sig) == nil
+	case ES256:
+		publicKey, ok := key.PublicKey.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return false
+		}
+		hashed := sha256.Sum256(input)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(publicKey, hashed[:], r, s)
+	case EdDSA:
+		publicKey, ok := key.PublicKey.(ed25519.PublicKey)
+		if !ok {
+			return false
+		}
+		return ed25519.Verify(publicKey, input, sig)
+	default:
+		return false
+	}
+}
+
+func toClaims(payload interface{}) (map[string]interface{}, error) {
+	claims := make(map[string]interface{})
+	if payload == nil {
+		return claims, nil
+	}
+	if m, ok := payload.(map[string]interface{}); ok {
+		for k, v := range m {
+			claims[k] = v
+		}
+		return claims, nil
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &claims)
+	return claims, err
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case int64:
+		return n
+	case json.Number:
+		i, _ := n.Int64()
+		return i
+	default:
+		return 0
+	}
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJSON(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
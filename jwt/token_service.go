@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"time"
+)
+
+type TokenService struct {
+	Issuer   string
+	Audience []string
+	Subject  string
+	Leeway   time.Duration
+	Keys     *KeyManager
+	Resolver KeyResolver
+}
+
+func NewTokenService(conf Config, options ...*KeyManager) *TokenService {
+	s := &TokenService{Issuer: conf.Issuer, Audience: conf.Audience, Subject: conf.Subject, Leeway: time.Duration(conf.Leeway) * time.Second}
+	if len(conf.Subject) == 0 {
+		s.Subject = "id"
+	}
+	if len(options) > 0 && options[0] != nil {
+		s.Keys = options[0]
+		s.Resolver = options[0]
+	}
+	return s
+}
+
+func (s *TokenService) GenerateToken(payload interface{}, secret string, expiresIn int64) (string, error) {
+	claims, err := toClaims(payload)
+	if err != nil {
+		return "", err
+	}
+	jti, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["jti"] = jti
+	if expiresIn > 0 {
+		claims["exp"] = now.Add(time.Duration(expiresIn) * time.Millisecond).Unix()
+	}
+	if len(s.Issuer) > 0 {
+		claims["iss"] = s.Issuer
+	}
+	if len(s.Audience) == 1 {
+		claims["aud"] = s.Audience[0]
+	} else if len(s.Audience) > 1 {
+		claims["aud"] = s.Audience
+	}
+	if sub, ok := claims[s.Subject].(string); ok && len(sub) > 0 {
+		claims["sub"] = sub
+	}
+	var key *Key
+	if s.Keys != nil {
+		key, err = s.Keys.Current()
+		if err != nil {
+			return "", err
+		}
+	} else {
+		key = NewHMACKey("", secret)
+	}
+	return SignClaims(key, claims)
+}
+
+func (s *TokenService) VerifyToken(tokenString string, secret string) (map[string]interface{}, int64, int64, error) {
+	var resolver KeyResolver
+	if s.Resolver != nil {
+		resolver = s.Resolver
+	} else if s.Keys != nil {
+		resolver = s.Keys
+	}
+	resolve := func(kid string) (*Key, error) {
+		// the shared secret is only used when no asymmetric key set is configured
+		if resolver == nil {
+			return NewHMACKey(kid, secret), nil
+		}
+		if len(kid) == 0 {
+			return nil, ErrUnknownKey
+		}
+		return resolver.Resolve(kid)
+	}
+	claims, err := ParseClaims(tokenString, resolve)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	iat := toInt64(claims["iat"])
+	exp := toInt64(claims["exp"])
+	if err = ValidateTime(claims, time.Now(), s.Leeway); err != nil {
+		return claims, iat, exp, err
+	}
+	if len(s.Issuer) > 0 {
+		if iss, _ := claims["iss"].(string); iss != s.Issuer {
+			return claims, iat, exp, ErrInvalidIssuer
+		}
+	}
+	if len(s.Audience) > 0 && !containsAudience(claims["aud"], s.Audience) {
+		return claims, iat, exp, ErrInvalidAudience
+	}
+	return claims, iat, exp, nil
+}
+
+func containsAudience(aud interface{}, audience []string) bool {
+	var values []string
+	switch v := aud.(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	for _, v := range values {
+		for _, a := range audience {
+			if v == a {
+				return true
+			}
+		}
+	}
+	return false
+}
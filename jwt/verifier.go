@@ -0,0 +1,17 @@
+package jwt
+
+type KeyResolver interface {
+	Resolve(kid string) (*Key, error)
+}
+
+type Verifier struct {
+	Keys KeyResolver
+}
+
+func NewVerifier(keys KeyResolver) *Verifier {
+	return &Verifier{Keys: keys}
+}
+
+func (v *Verifier) VerifyToken(tokenString string, secret string) (map[string]interface{}, int64, int64, error) {
+	return Parse(tokenString, v.Keys.Resolve)
+}
@@ -18,6 +18,7 @@ type LDAPAuthenticator struct {
 	Config LDAPConfig
 	Domain string
 	Status auth.Status
+	Events auth.AuthEventSink
 }
 
 func GetDomain(baseDN string) (string, error) {
@@ -72,6 +73,22 @@ func NewConn(c LDAPConfig) (*ldap.Conn, error) {
 	return l, err
 }
 func (s *LDAPAuthenticator) Authenticate(ctx context.Context, info auth.AuthInfo) (auth.AuthResult, error) {
+	result, err := s.authenticate(ctx, info)
+	if s.Events != nil {
+		event := auth.AuthEvent{Type: auth.EventLoginFailure, Username: info.Username, Ip: info.Ip, Device: info.Device, Provider: "ldap", Status: result.Status}
+		if err != nil {
+			event.Reason = err.Error()
+		} else if result.Status == s.Status.Success {
+			event.Type = auth.EventLoginSuccess
+		}
+		if result.User != nil {
+			event.UserId = result.User.Id
+		}
+		auth.WriteEvent(ctx, s.Events, event)
+	}
+	return result, err
+}
+func (s *LDAPAuthenticator) authenticate(ctx context.Context, info auth.AuthInfo) (auth.AuthResult, error) {
 	result := auth.AuthResult{}
 	account := auth.UserAccount{}
 	result.Status = s.Status.Fail
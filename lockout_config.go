@@ -0,0 +1,12 @@
+package auth
+
+type LockoutConfig struct {
+	MaxPasswordFailed int     `yaml:"max_password_failed" mapstructure:"max_password_failed" json:"maxPasswordFailed,omitempty" gorm:"column:maxpasswordfailed" bson:"maxPasswordFailed,omitempty" dynamodbav:"maxPasswordFailed,omitempty" firestore:"maxPasswordFailed,omitempty"`
+	LockedMinutes     int     `yaml:"locked_minutes" mapstructure:"locked_minutes" json:"lockedMinutes,omitempty" gorm:"column:lockedminutes" bson:"lockedMinutes,omitempty" dynamodbav:"lockedMinutes,omitempty" firestore:"lockedMinutes,omitempty"`
+	Backoff           string  `yaml:"backoff" mapstructure:"backoff" json:"backoff,omitempty" gorm:"column:backoff" bson:"backoff,omitempty" dynamodbav:"backoff,omitempty" firestore:"backoff,omitempty"`
+	Multiplier        float64 `yaml:"multiplier" mapstructure:"multiplier" json:"multiplier,omitempty" gorm:"column:multiplier" bson:"multiplier,omitempty" dynamodbav:"multiplier,omitempty" firestore:"multiplier,omitempty"`
+	Steps             []int   `yaml:"steps" mapstructure:"steps" json:"steps,omitempty" gorm:"column:steps" bson:"steps,omitempty" dynamodbav:"steps,omitempty" firestore:"steps,omitempty"`
+	MaxLockedMinutes  int     `yaml:"max_locked_minutes" mapstructure:"max_locked_minutes" json:"maxLockedMinutes,omitempty" gorm:"column:maxlockedminutes" bson:"maxLockedMinutes,omitempty" dynamodbav:"maxLockedMinutes,omitempty" firestore:"maxLockedMinutes,omitempty"`
+	DecayMinutes      int     `yaml:"decay_minutes" mapstructure:"decay_minutes" json:"decayMinutes,omitempty" gorm:"column:decayminutes" bson:"decayMinutes,omitempty" dynamodbav:"decayMinutes,omitempty" firestore:"decayMinutes,omitempty"`
+	MaxLockouts       int     `yaml:"max_lockouts" mapstructure:"max_lockouts" json:"maxLockouts,omitempty" gorm:"column:maxlockouts" bson:"maxLockouts,omitempty" dynamodbav:"maxLockouts,omitempty" firestore:"maxLockouts,omitempty"`
+}
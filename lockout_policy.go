@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	BackoffFixed       = "fixed"
+	BackoffExponential = "exponential"
+	BackoffStepped     = "stepped"
+)
+
+var LockedForever = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// maxLockedMinutes (100 years) keeps time.Duration(minutes) * time.Minute from overflowing
+const maxLockedMinutes = 100 * 365 * 24 * 60
+
+type LockoutPolicy interface {
+	Fail(user UserInfo, now time.Time) (int, *time.Time)
+}
+
+type ProgressiveLockout struct {
+	Config LockoutConfig
+}
+
+func NewLockoutPolicy(conf LockoutConfig) *ProgressiveLockout {
+	if len(conf.Backoff) == 0 {
+		conf.Backoff = BackoffFixed
+	}
+	if conf.Multiplier <= 1 {
+		conf.Multiplier = 2
+	}
+	return &ProgressiveLockout{Config: conf}
+}
+
+// Fail never lowers an active lock: the later of the current lock and the computed one is returned.
+// Failures while the lock is active are not escalated.
+func (p *ProgressiveLockout) Fail(user UserInfo, now time.Time) (int, *time.Time) {
+	if user.LockedUntilTime != nil && user.LockedUntilTime.After(now) {
+		failCount := 0
+		if user.FailCount != nil {
+			failCount = *user.FailCount
+		}
+		return failCount, user.LockedUntilTime
+	}
+	failCount, lockedUntil := p.fail(user, now)
+	return failCount, KeepLock(user.LockedUntilTime, lockedUntil, now)
+}
+func (p *ProgressiveLockout) fail(user UserInfo, now time.Time) (int, *time.Time) {
+	c := p.Config
+	failCount := 0
+	if user.FailCount != nil {
+		failCount = *user.FailCount
+	}
+	if c.DecayMinutes > 0 && user.FailTime != nil && now.Sub(*user.FailTime) >= time.Duration(c.DecayMinutes)*time.Minute {
+		failCount = 0
+	}
+	if c.MaxPasswordFailed <= 0 || failCount+1 < c.MaxPasswordFailed {
+		return failCount, nil
+	}
+	lockouts := failCount + 2 - c.MaxPasswordFailed
+	if c.MaxLockouts > 0 && lockouts >= c.MaxLockouts {
+		return failCount, &LockedForever
+	}
+	minutes := p.LockedMinutes(lockouts)
+	if minutes <= 0 {
+		return failCount, nil
+	}
+	lockedUntil := now.Add(time.Duration(minutes) * time.Minute)
+	return failCount, &lockedUntil
+}
+
+// KeepLock returns the later of the current lock, when it is still active, and the computed one.
+func KeepLock(current *time.Time, computed *time.Time, now time.Time) *time.Time {
+	if current == nil || !current.After(now) {
+		return computed
+	}
+	if computed == nil || computed.Before(*current) {
+		return current
+	}
+	return computed
+}
+
+func (p *ProgressiveLockout) LockedMinutes(lockouts int) int {
+	c := p.Config
+	minutes := c.LockedMinutes
+	switch c.Backoff {
+	case BackoffExponential:
+		m := float64(c.LockedMinutes) * math.Pow(c.Multiplier, float64(lockouts-1))
+		if m > maxLockedMinutes {
+			m = maxLockedMinutes
+		}
+		minutes = int(m)
+	case BackoffStepped:
+		if len(c.Steps) > 0 {
+			if lockouts > len(c.Steps) {
+				minutes = c.Steps[len(c.Steps)-1]
+			} else {
+				minutes = c.Steps[lockouts-1]
+			}
+		}
+	}
+	if c.MaxLockedMinutes > 0 && minutes > c.MaxLockedMinutes {
+		minutes = c.MaxLockedMinutes
+	}
+	if minutes > maxLockedMinutes {
+		minutes = maxLockedMinutes
+	}
+	return minutes
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+)
+
+type LoginRateLimiter struct {
+	Prefix   string
+	Ip       RateLimiter
+	Username RateLimiter
+	Device   RateLimiter
+}
+
+func NewLoginRateLimiter(conf RateLimitConfig, store RateLimitStore) *LoginRateLimiter {
+	prefix := conf.Prefix
+	if len(prefix) == 0 {
+		prefix = "ratelimit:"
+	}
+	return &LoginRateLimiter{
+		Prefix:   prefix,
+		Ip:       NewRateLimiter(conf.Algorithm, store, conf.Ip),
+		Username: NewRateLimiter(conf.Algorithm, store, conf.Username),
+		Device:   NewRateLimiter(conf.Algorithm, store, conf.Device),
+	}
+}
+
+func NewRateLimiter(algorithm string, store RateLimitStore, rule RateLimitRule) RateLimiter {
+	if rule.Limit <= 0 || rule.Window <= 0 {
+		return nil
+	}
+	window := time.Duration(rule.Window) * time.Second
+	if algorithm == AlgorithmSlidingWindow {
+		return NewSlidingWindow(store, rule.Limit, window)
+	}
+	return NewTokenBucket(store, rule.Limit, window)
+}
+
+func (l *LoginRateLimiter) Allow(ctx context.Context, ip string, info AuthInfo) (bool, time.Duration, error) {
+	if l.Ip != nil && len(ip) > 0 {
+		if allowed, retryAfter, err := l.Ip.Allow(ctx, l.Prefix+"ip:"+ip); err != nil || !allowed {
+			return allowed, retryAfter, err
+		}
+	}
+	// usernames and devices are only unique within a tenant, so their buckets are per tenant; the ip bucket stays global
+	prefix := l.Prefix
+	if tenant := GetTenant(ctx); len(tenant) > 0 {
+		prefix = prefix + "tenant:" + tenant + ":"
+	}
+	if l.Username != nil && len(info.Username) > 0 {
+		if allowed, retryAfter, err := l.Username.Allow(ctx, prefix+"username:"+strings.ToLower(info.Username)); err != nil || !allowed {
+			return allowed, retryAfter, err
+		}
+	}
+	if l.Device != nil && len(info.Device) > 0 {
+		if allowed, retryAfter, err := l.Device.Allow(ctx, prefix+"device:"+info.Device); err != nil || !allowed {
+			return allowed, retryAfter, err
+		}
+	}
+	return true, 0, nil
+}
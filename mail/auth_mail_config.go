@@ -6,8 +6,10 @@ import (
 )
 
 type AuthMailConfig struct {
-	Secret   string              `yaml:"secret" mapstructure:"secret" json:"secret,omitempty" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
-	Expires  int64               `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
-	Schema   auth.SchemaConfig   `yaml:"schema" mapstructure:"schema" json:"schema,omitempty" gorm:"column:schema" bson:"schema,omitempty" dynamodbav:"schema,omitempty" firestore:"schema,omitempty"`
-	Template mail.TemplateConfig `yaml:"template" mapstructure:"template" json:"template,omitempty" gorm:"column:template" bson:"template,omitempty" dynamodbav:"template,omitempty" firestore:"template,omitempty"`
+	Secret   string                    `yaml:"secret" mapstructure:"secret" json:"secret,omitempty" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
+	Expires  int64                     `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
+	History  int                       `yaml:"history" mapstructure:"history" json:"history,omitempty" gorm:"column:history" bson:"history,omitempty" dynamodbav:"history,omitempty" firestore:"history,omitempty"`
+	Policy   auth.PasswordPolicyConfig `yaml:"policy" mapstructure:"policy" json:"policy,omitempty" gorm:"column:policy" bson:"policy,omitempty" dynamodbav:"policy,omitempty" firestore:"policy,omitempty"`
+	Schema   auth.SchemaConfig         `yaml:"schema" mapstructure:"schema" json:"schema,omitempty" gorm:"column:schema" bson:"schema,omitempty" dynamodbav:"schema,omitempty" firestore:"schema,omitempty"`
+	Template mail.TemplateConfig       `yaml:"template" mapstructure:"template" json:"template,omitempty" gorm:"column:template" bson:"template,omitempty" dynamodbav:"template,omitempty" firestore:"template,omitempty"`
 }
@@ -0,0 +1,84 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	htmltemplate "html/template"
+	"net/url"
+	"text/template"
+	"time"
+
+	auth "github.com/core-go/authentication"
+)
+
+type MailData struct {
+	Username    string
+	DisplayName string
+	Code        string
+	Link        string
+	ExpireAt    time.Time
+	Expires     int64
+}
+
+type PasswordMailSender struct {
+	Send    func(ctx context.Context, to string, subject string, body string) error
+	Subject *template.Template
+	Body    *htmltemplate.Template
+	Url     string
+}
+
+func NewPasswordMailSender(conf AuthMailConfig, send func(context.Context, string, string, string) error, options ...string) (*PasswordMailSender, error) {
+	if send == nil {
+		return nil, errors.New("send function cannot be nil")
+	}
+	subject, err := template.New("subject").Parse(conf.Template.Subject)
+	if err != nil {
+		return nil, err
+	}
+	// the body is HTML, so the user's name and the link are escaped
+	body, err := htmltemplate.New("body").Parse(conf.Template.Body)
+	if err != nil {
+		return nil, err
+	}
+	var link string
+	if len(options) > 0 {
+		link = options[0]
+	}
+	return &PasswordMailSender{Send: send, Subject: subject, Body: body, Url: link}, nil
+}
+
+func (s *PasswordMailSender) SendCode(ctx context.Context, to string, code string, expireAt time.Time, params interface{}) error {
+	data := MailData{Code: code, ExpireAt: expireAt, Expires: int64(time.Until(expireAt).Round(time.Minute).Minutes())}
+	if len(s.Url) > 0 {
+		data.Link = s.Url + url.QueryEscape(code)
+	}
+	if user, ok := params.(*auth.UserInfo); ok && user != nil {
+		data.Username = user.Username
+		if user.DisplayName != nil {
+			data.DisplayName = *user.DisplayName
+		}
+	}
+	var subject, body bytes.Buffer
+	if err := s.Subject.Execute(&subject, data); err != nil {
+		return err
+	}
+	if err := s.Body.Execute(&body, data); err != nil {
+		return err
+	}
+	return s.Send(ctx, to, subject.String(), body.String())
+}
+
+func NewPasswordService(conf AuthMailConfig, repository auth.PasswordRepository, comparator auth.ValueComparator, codeRepository auth.CodeRepository, send func(context.Context, string, string, string) error, options ...string) (*auth.PasswordService, error) {
+	sender, err := NewPasswordMailSender(conf, send, options...)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := auth.NewPasswordPolicy(conf.Policy)
+	if err != nil {
+		return nil, err
+	}
+	service := auth.NewPasswordService(repository, comparator, codeRepository, sender.SendCode, conf.Secret, conf.Expires, conf.History)
+	service.Policy = policy
+	return service, nil
+}
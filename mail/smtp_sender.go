@@ -0,0 +1,138 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	auth "github.com/core-go/authentication"
+	"github.com/core-go/mail"
+)
+
+type SmtpConfig struct {
+	Host     string `yaml:"host" mapstructure:"host" json:"host,omitempty" gorm:"column:host" bson:"host,omitempty" dynamodbav:"host,omitempty" firestore:"host,omitempty"`
+	Port     int    `yaml:"port" mapstructure:"port" json:"port,omitempty" gorm:"column:port" bson:"port,omitempty" dynamodbav:"port,omitempty" firestore:"port,omitempty"`
+	Username string `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Password string `yaml:"password" mapstructure:"password" json:"password,omitempty" gorm:"column:password" bson:"password,omitempty" dynamodbav:"password,omitempty" firestore:"password,omitempty"`
+	From     string `yaml:"from" mapstructure:"from" json:"from,omitempty" gorm:"column:from" bson:"from,omitempty" dynamodbav:"from,omitempty" firestore:"from,omitempty"`
+	Timeout  int64  `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+}
+
+type SmtpSender struct {
+	Config    SmtpConfig
+	Templates *auth.CodeTemplates
+	SendMail  func(ctx context.Context, to string, subject string, body string) error
+}
+
+// NewSmtpSender uses conf.Template as the default template; templates adds the localized ones, keyed by language.
+func NewSmtpSender(conf AuthMailConfig, c SmtpConfig, templates ...map[string]mail.TemplateConfig) (*SmtpSender, error) {
+	if len(c.Host) == 0 {
+		return nil, errors.New("smtp host cannot be empty")
+	}
+	if c.Port <= 0 {
+		c.Port = 587
+	}
+	if len(c.From) == 0 {
+		c.From = c.Username
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30000
+	}
+	m := map[string]auth.CodeTemplate{"": {Subject: conf.Template.Subject, Body: conf.Template.Body}}
+	if len(templates) > 0 {
+		for language, t := range templates[0] {
+			m[language] = auth.CodeTemplate{Subject: t.Subject, Body: t.Body}
+		}
+	}
+	t, err := auth.NewHtmlCodeTemplates(m)
+	if err != nil {
+		return nil, err
+	}
+	s := &SmtpSender{Config: c, Templates: t}
+	s.SendMail = s.send
+	return s, nil
+}
+
+func (s *SmtpSender) Send(ctx context.Context, msg auth.CodeMessage) error {
+	subject, body, err := s.Templates.Render(msg)
+	if err != nil {
+		return err
+	}
+	return s.SendMail(ctx, msg.To, subject, body)
+}
+
+// send is smtp.SendMail bound to ctx: the connection is closed when ctx is done, and the whole exchange is limited by
+// the ctx deadline or Config.Timeout, in milliseconds.
+func (s *SmtpSender) send(ctx context.Context, to string, subject string, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return errors.New("invalid mail header")
+	}
+	header := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\n", s.Config.From, to, subject)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.Config.Timeout)*time.Millisecond)
+	defer cancel()
+	var d net.Dialer
+	conn, er1 := d.DialContext(ctx, "tcp", net.JoinHostPort(s.Config.Host, strconv.Itoa(s.Config.Port)))
+	if er1 != nil {
+		return er1
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	if err := s.deliver(conn, to, []byte(header+body)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SmtpSender) deliver(conn net.Conn, to string, msg []byte) error {
+	c, er1 := smtp.NewClient(conn, s.Config.Host)
+	if er1 != nil {
+		conn.Close()
+		return er1
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if er2 := c.StartTLS(&tls.Config{ServerName: s.Config.Host}); er2 != nil {
+			return er2
+		}
+	}
+	if len(s.Config.Username) > 0 {
+		if er3 := c.Auth(smtp.PlainAuth("", s.Config.Username, s.Config.Password, s.Config.Host)); er3 != nil {
+			return er3
+		}
+	}
+	if er4 := c.Mail(s.Config.From); er4 != nil {
+		return er4
+	}
+	if er5 := c.Rcpt(to); er5 != nil {
+		return er5
+	}
+	w, er6 := c.Data()
+	if er6 != nil {
+		return er6
+	}
+	if _, er7 := w.Write(msg); er7 != nil {
+		return er7
+	}
+	if er8 := w.Close(); er8 != nil {
+		return er8
+	}
+	return c.Quit()
+}
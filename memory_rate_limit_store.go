@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type rateLimitItem struct {
+	Value    string
+	ExpireAt time.Time
+}
+
+type MemoryRateLimitStore struct {
+	mu    sync.Mutex
+	items map[string]rateLimitItem
+	puts  int
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{items: make(map[string]rateLimitItem)}
+}
+
+func (s *MemoryRateLimitStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[key]
+	if !ok {
+		return "", nil
+	}
+	if time.Now().After(item.ExpireAt) {
+		delete(s.items, key)
+		return "", nil
+	}
+	return item.Value, nil
+}
+
+func (s *MemoryRateLimitStore) Put(ctx context.Context, key string, value string, timeToLive time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.items[key] = rateLimitItem{Value: value, ExpireAt: now.Add(timeToLive)}
+	s.puts++
+	if s.puts >= 1000 {
+		s.puts = 0
+		for k, item := range s.items {
+			if now.After(item.ExpireAt) {
+				delete(s.items, k)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss is returned by MemoryStore.Get for a missing or expired key; check it with IsCacheMiss.
+var ErrCacheMiss = errors.New("cache miss")
+
+// ErrProtectedFull is returned when a protected key cannot be stored because MaxProtected is reached.
+var ErrProtectedFull = errors.New("memory store is full of protected keys")
+
+type memoryItem struct {
+	Key      string
+	Value    string
+	ExpireAt time.Time
+	Attempts int
+}
+
+// MemoryStore is an in-process cache with TTL and LRU eviction; it implements CachePort, CodeRepository, handler.StoreService and authorizer.CachePort.
+// Keys matched by Protected hold security state, such as revoked tokens, and are never evicted to respect MaxSize,
+// only expired; evicting them would silently un-revoke a token. They do not count against MaxSize: MaxProtected
+// bounds them instead, and storing a new one beyond it fails with ErrProtectedFull.
+type MemoryStore struct {
+	MaxSize      int
+	MaxProtected int
+	CodePrefix   string
+	Protected    func(key string) bool
+	mu           sync.Mutex
+	items        map[string]*list.Element
+	order        *list.List
+	pinned       *list.List
+	stop         chan struct{}
+	closed       bool
+}
+
+func NewMemoryStore(maxSize int, options ...time.Duration) *MemoryStore {
+	var sweepInterval time.Duration
+	if len(options) > 0 {
+		sweepInterval = options[0]
+	} else {
+		sweepInterval = time.Minute
+	}
+	s := &MemoryStore{MaxSize: maxSize, CodePrefix: "passcode:", Protected: IsSecurityKey, items: make(map[string]*list.Element), order: list.New(), pinned: list.New(), stop: make(chan struct{})}
+	if sweepInterval > 0 {
+		go s.sweep(sweepInterval)
+	}
+	return s
+}
+
+func (s *MemoryStore) Put(ctx context.Context, key string, obj interface{}, timeToLive time.Duration) error {
+	var value string
+	switch v := obj.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		value = string(b)
+	}
+	var expireAt time.Time
+	if timeToLive > 0 {
+		expireAt = time.Now().Add(timeToLive)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set(key, value, expireAt)
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.get(key, time.Now())
+	if !ok {
+		return "", ErrCacheMiss
+	}
+	return item.Value, nil
+}
+
+// Add stores the value only when the key is absent or expired, reporting whether it was stored.
+func (s *MemoryStore) Add(ctx context.Context, key string, value string, timeToLive time.Duration) (bool, error) {
+	var expireAt time.Time
+	if timeToLive > 0 {
+		expireAt = time.Now().Add(timeToLive)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.get(key, time.Now()); ok {
+		return false, nil
+	}
+	if err := s.set(key, value, expireAt); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) GetMany(ctx context.Context, keys []string) (map[string]string, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	values := make(map[string]string)
+	missing := make([]string, 0)
+	for _, key := range keys {
+		if item, ok := s.get(key, now); ok {
+			values[key] = item.Value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return values, missing, nil
+}
+
+func (s *MemoryStore) Remove(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.get(key, time.Now())
+	if ok {
+		s.remove(key)
+	}
+	return ok, nil
+}
+
+func (s *MemoryStore) Expire(ctx context.Context, key string, timeToLive time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.get(key, time.Now())
+	if !ok {
+		return false, nil
+	}
+	// as in Redis, a ttl that is not positive deletes the key
+	if timeToLive <= 0 {
+		s.remove(key)
+		return true, nil
+	}
+	item.ExpireAt = time.Now().Add(timeToLive)
+	return true, nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, id string, code string, expireAt time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.set(s.CodePrefix+id, code, expireAt); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (s *MemoryStore) Load(ctx context.Context, id string) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.get(s.CodePrefix+id, time.Now())
+	if !ok {
+		return "", time.Time{}, nil
+	}
+	return item.Value, item.ExpireAt, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string) (int64, error) {
+	ok, err := s.Remove(ctx, s.CodePrefix+id)
+	if ok {
+		return 1, err
+	}
+	return 0, err
+}
+
+func (s *MemoryStore) IncreaseAttempts(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.get(s.CodePrefix+id, time.Now())
+	if !ok {
+		return 0, nil
+	}
+	item.Attempts++
+	return item.Attempts, nil
+}
+
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len() + s.pinned.Len()
+}
+
+// Close stops the background sweeper.
+func (s *MemoryStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+}
+
+func (s *MemoryStore) set(key string, value string, expireAt time.Time) error {
+	if e, ok := s.items[key]; ok {
+		item := e.Value.(*memoryItem)
+		item.Value = value
+		item.ExpireAt = expireAt
+		item.Attempts = 0
+		s.order.MoveToFront(e)
+		return nil
+	}
+	item := &memoryItem{Key: key, Value: value, ExpireAt: expireAt}
+	if s.Protected != nil && s.Protected(key) {
+		if s.MaxProtected > 0 && s.pinned.Len() >= s.MaxProtected {
+			s.purgeProtected(time.Now())
+			if s.pinned.Len() >= s.MaxProtected {
+				return ErrProtectedFull
+			}
+		}
+		s.items[key] = s.pinned.PushFront(item)
+		return nil
+	}
+	s.items[key] = s.order.PushFront(item)
+	if s.MaxSize > 0 {
+		for s.order.Len() > 0 && s.order.Len() > s.MaxSize {
+			s.remove(s.order.Back().Value.(*memoryItem).Key)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) purgeProtected(now time.Time) {
+	for e := s.pinned.Front(); e != nil; {
+		next := e.Next()
+		item := e.Value.(*memoryItem)
+		if !item.ExpireAt.IsZero() && now.After(item.ExpireAt) {
+			s.remove(item.Key)
+		}
+		e = next
+	}
+}
+
+func (s *MemoryStore) get(key string, now time.Time) (*memoryItem, bool) {
+	e, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := e.Value.(*memoryItem)
+	if !item.ExpireAt.IsZero() && now.After(item.ExpireAt) {
+		s.remove(key)
+		return nil, false
+	}
+	s.order.MoveToFront(e)
+	return item, true
+}
+
+func (s *MemoryStore) remove(key string) {
+	if e, ok := s.items[key]; ok {
+		s.order.Remove(e)
+		s.pinned.Remove(e)
+		delete(s.items, key)
+	}
+}
+
+// IsSecurityKey matches the keys of the token blacklist and whitelist checkers and of the refresh token store
+// with its default prefix.
+func IsSecurityKey(key string) bool {
+	return strings.Contains(key, "::token::") || strings.HasPrefix(key, "refresh:family:") || strings.HasPrefix(key, "refresh:used:")
+}
+
+func (s *MemoryStore) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, e := range s.items {
+				item := e.Value.(*memoryItem)
+				if !item.ExpireAt.IsZero() && now.After(item.ExpireAt) {
+					s.remove(key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CodeRepository struct {
+	Collection    *mongo.Collection
+	CodeName      string
+	ExpiredAtName string
+	AttemptsName  string
+}
+
+func NewCodeAdapter(db *mongo.Database, collectionName string, opts ...string) *CodeRepository {
+	return NewCodeRepository(db, collectionName, opts...)
+}
+func NewCodeRepository(db *mongo.Database, collectionName string, opts ...string) *CodeRepository {
+	var codeName, expiredAtName, attemptsName string
+	if len(opts) > 0 && len(opts[0]) > 0 {
+		codeName = opts[0]
+	} else {
+		codeName = "code"
+	}
+	if len(opts) > 1 && len(opts[1]) > 0 {
+		expiredAtName = opts[1]
+	} else {
+		expiredAtName = "expiredAt"
+	}
+	if len(opts) > 2 && len(opts[2]) > 0 {
+		attemptsName = opts[2]
+	} else {
+		attemptsName = "attempts"
+	}
+	if len(collectionName) == 0 {
+		collectionName = "passcodes"
+	}
+	return &CodeRepository{Collection: db.Collection(collectionName), CodeName: codeName, ExpiredAtName: expiredAtName, AttemptsName: attemptsName}
+}
+
+func (r *CodeRepository) Save(ctx context.Context, id string, code string, expireAt time.Time) (int64, error) {
+	doc := bson.M{"_id": id, r.CodeName: code, r.ExpiredAtName: expireAt, r.AttemptsName: 0}
+	res, err := r.Collection.ReplaceOne(ctx, bson.M{"_id": id}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount + res.UpsertedCount, nil
+}
+
+func (r *CodeRepository) Load(ctx context.Context, id string) (string, time.Time, error) {
+	result := r.Collection.FindOne(ctx, bson.M{"_id": id})
+	if result.Err() != nil {
+		if fmt.Sprint(result.Err()) == "mongo: no documents in result" {
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, result.Err()
+	}
+	raw, er1 := result.DecodeBytes()
+	if er1 != nil {
+		return "", time.Time{}, er1
+	}
+	code, _ := raw.Lookup(r.CodeName).StringValueOK()
+	expiredAt, ok := raw.Lookup(r.ExpiredAtName).TimeOK()
+	if !ok || expiredAt.Before(time.Now()) {
+		return "", time.Time{}, nil
+	}
+	return code, expiredAt, nil
+}
+
+func (r *CodeRepository) IncreaseAttempts(ctx context.Context, id string) (int, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{r.AttemptsName: 1})
+	result := r.Collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{r.AttemptsName: 1}}, opts)
+	if result.Err() != nil {
+		if fmt.Sprint(result.Err()) == "mongo: no documents in result" {
+			return 0, nil
+		}
+		return 0, result.Err()
+	}
+	raw, err := result.DecodeBytes()
+	if err != nil {
+		return 0, err
+	}
+	attempts, _ := raw.Lookup(r.AttemptsName).AsInt64OK()
+	return int(attempts), nil
+}
+
+func (r *CodeRepository) Delete(ctx context.Context, id string) (int64, error) {
+	res, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *CodeRepository) Purge(ctx context.Context) (int64, error) {
+	res, err := r.Collection.DeleteMany(ctx, bson.M{r.ExpiredAtName: bson.M{"$lt": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
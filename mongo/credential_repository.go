@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	a "github.com/core-go/authentication"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type CredentialRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCredentialAdapter(db *mongo.Database, collectionName string) *CredentialRepository {
+	return NewCredentialRepository(db, collectionName)
+}
+func NewCredentialRepository(db *mongo.Database, collectionName string) *CredentialRepository {
+	return &CredentialRepository{Collection: db.Collection(collectionName)}
+}
+
+func (r *CredentialRepository) Load(ctx context.Context, userId string) ([]a.WebAuthnCredential, error) {
+	var credentials []a.WebAuthnCredential
+	cursor, err := r.Collection.Find(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &credentials)
+	return credentials, err
+}
+func (r *CredentialRepository) Get(ctx context.Context, id string) (*a.WebAuthnCredential, error) {
+	var credential a.WebAuthnCredential
+	result := r.Collection.FindOne(ctx, bson.M{"_id": id})
+	if result.Err() != nil {
+		if fmt.Sprint(result.Err()) == "mongo: no documents in result" {
+			return nil, nil
+		}
+		return nil, result.Err()
+	}
+	if err := result.Decode(&credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+func (r *CredentialRepository) Save(ctx context.Context, credential a.WebAuthnCredential) (int64, error) {
+	return insertOne(ctx, r.Collection, &credential)
+}
+func (r *CredentialRepository) Update(ctx context.Context, id string, signCount int64, lastUsedTime time.Time) (int64, error) {
+	update := bson.M{"$set": bson.M{"signCount": signCount, "lastUsedTime": lastUsedTime}}
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+func (r *CredentialRepository) Delete(ctx context.Context, userId string, id string) (int64, error) {
+	result, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,173 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	a "github.com/core-go/authentication"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordRepository struct {
+	UserCollection          *mongo.Collection
+	PasswordCollection      *mongo.Collection
+	HistoryCollection       *mongo.Collection
+	UserName                string
+	UserIdName              string
+	PasswordName            string
+	PasswordChangedTimeName string
+	FailCountName           string
+	LockedUntilTimeName     string
+	ContactName             string
+	EmailName               string
+	DisplayNameName         string
+	LanguageName            string
+}
+
+func NewPasswordAdapterByConfig(db *mongo.Database, userCollectionName, passwordCollectionName, historyCollectionName string, c a.SchemaConfig) *PasswordRepository {
+	return NewPasswordRepositoryByConfig(db, userCollectionName, passwordCollectionName, historyCollectionName, c)
+}
+func NewPasswordRepositoryByConfig(db *mongo.Database, userCollectionName, passwordCollectionName, historyCollectionName string, c a.SchemaConfig) *PasswordRepository {
+	if len(passwordCollectionName) == 0 {
+		passwordCollectionName = userCollectionName
+	}
+	passwordCollection := db.Collection(passwordCollectionName)
+	userCollection := passwordCollection
+	if passwordCollectionName != userCollectionName {
+		userCollection = db.Collection(userCollectionName)
+	}
+	var historyCollection *mongo.Collection
+	if len(historyCollectionName) > 0 {
+		historyCollection = db.Collection(historyCollectionName)
+	}
+	userName := c.Username
+	if len(userName) == 0 {
+		userName = "username"
+	}
+	userIdName := c.UserId
+	if len(userIdName) == 0 {
+		userIdName = "userId"
+	}
+	passwordName := c.Password
+	if len(passwordName) == 0 {
+		passwordName = "password"
+	}
+	return &PasswordRepository{UserCollection: userCollection, PasswordCollection: passwordCollection, HistoryCollection: historyCollection, UserName: userName, UserIdName: userIdName, PasswordName: passwordName, PasswordChangedTimeName: c.PasswordChangedTime, FailCountName: c.FailCount, LockedUntilTimeName: c.LockedUntilTime, ContactName: c.Contact, EmailName: c.Email, DisplayNameName: c.DisplayName, LanguageName: c.Language}
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*a.UserInfo, error) {
+	query := bson.M{r.UserName: contact}
+	if len(r.EmailName) > 0 {
+		query = bson.M{"$or": []bson.M{{r.UserName: contact}, {r.EmailName: contact}}}
+	}
+	result := r.UserCollection.FindOne(ctx, query)
+	if result.Err() != nil {
+		if fmt.Sprint(result.Err()) == "mongo: no documents in result" {
+			return nil, nil
+		}
+		return nil, result.Err()
+	}
+	raw, er1 := result.DecodeBytes()
+	if er1 != nil {
+		return nil, er1
+	}
+	user := a.UserInfo{}
+	if id, ok := raw.Lookup("_id").StringValueOK(); ok {
+		user.Id = id
+	}
+	if username, ok := raw.Lookup(r.UserName).StringValueOK(); ok {
+		user.Username = username
+	}
+	if len(r.EmailName) > 0 {
+		if email, ok := raw.Lookup(r.EmailName).StringValueOK(); ok {
+			user.Email = &email
+		}
+	}
+	if len(r.ContactName) > 0 {
+		if contact, ok := raw.Lookup(r.ContactName).StringValueOK(); ok {
+			user.Contact = &contact
+		}
+	}
+	if len(r.DisplayNameName) > 0 {
+		if displayName, ok := raw.Lookup(r.DisplayNameName).StringValueOK(); ok {
+			user.DisplayName = &displayName
+		}
+	}
+	if len(r.LanguageName) > 0 {
+		if language, ok := raw.Lookup(r.LanguageName).StringValueOK(); ok {
+			user.Language = &language
+		}
+	}
+	if r.UserCollection.Name() != r.PasswordCollection.Name() {
+		resultPass := r.PasswordCollection.FindOne(ctx, bson.M{"_id": user.Id})
+		if resultPass.Err() != nil {
+			if fmt.Sprint(resultPass.Err()) == "mongo: no documents in result" {
+				return &user, nil
+			}
+			return nil, resultPass.Err()
+		}
+		raw, er1 = resultPass.DecodeBytes()
+		if er1 != nil {
+			return nil, er1
+		}
+	}
+	if password, ok := raw.Lookup(r.PasswordName).StringValueOK(); ok {
+		user.Password = password
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if r.HistoryCollection == nil || max <= 0 {
+		return passwords, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: r.historyTimeName(), Value: -1}}).SetLimit(int64(max))
+	cursor, err := r.HistoryCollection.Find(ctx, bson.M{r.UserIdName: id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		if password, ok := cursor.Current.Lookup(r.PasswordName).StringValueOK(); ok {
+			passwords = append(passwords, password)
+		}
+	}
+	return passwords, cursor.Err()
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	pass := bson.M{r.PasswordName: newPassword}
+	if len(r.PasswordChangedTimeName) > 0 {
+		pass[r.PasswordChangedTimeName] = now
+	}
+	if len(r.FailCountName) > 0 {
+		pass[r.FailCountName] = 0
+	}
+	if len(r.LockedUntilTimeName) > 0 {
+		pass[r.LockedUntilTimeName] = nil
+	}
+	result, err := r.PasswordCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": pass})
+	if err != nil {
+		return 0, err
+	}
+	if result.MatchedCount <= 0 || r.HistoryCollection == nil {
+		return result.MatchedCount, nil
+	}
+	history := bson.M{r.UserIdName: id, r.PasswordName: newPassword, r.historyTimeName(): now}
+	if _, err = r.HistoryCollection.InsertOne(ctx, history); err != nil {
+		return -1, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (r *PasswordRepository) historyTimeName() string {
+	if len(r.PasswordChangedTimeName) > 0 {
+		return r.PasswordChangedTimeName
+	}
+	return "passwordChangedTime"
+}
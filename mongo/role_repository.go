@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	a "github.com/core-go/authentication"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleRepository struct {
+	RoleCollection *mongo.Collection
+	UserCollection *mongo.Collection
+	RolesName      string
+}
+
+func NewRoleAdapter(db *mongo.Database, roleCollectionName, userCollectionName string, opts ...string) *RoleRepository {
+	return NewRoleRepository(db, roleCollectionName, userCollectionName, opts...)
+}
+func NewRoleRepository(db *mongo.Database, roleCollectionName, userCollectionName string, opts ...string) *RoleRepository {
+	var rolesName string
+	if len(opts) > 0 && len(opts[0]) > 0 {
+		rolesName = opts[0]
+	} else {
+		rolesName = "roles"
+	}
+	if len(roleCollectionName) == 0 {
+		roleCollectionName = "roles"
+	}
+	return &RoleRepository{RoleCollection: db.Collection(roleCollectionName), UserCollection: db.Collection(userCollectionName), RolesName: rolesName}
+}
+
+func (r *RoleRepository) All(ctx context.Context) ([]a.Role, error) {
+	cur, er1 := r.RoleCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if er1 != nil {
+		return nil, er1
+	}
+	roles := make([]a.Role, 0)
+	if er2 := cur.All(ctx, &roles); er2 != nil {
+		return nil, er2
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) GetUserRoles(ctx context.Context, userId string) ([]string, error) {
+	result := r.UserCollection.FindOne(ctx, bson.M{"_id": userId}, options.FindOne().SetProjection(bson.M{r.RolesName: 1}))
+	if result.Err() != nil {
+		if fmt.Sprint(result.Err()) == "mongo: no documents in result" {
+			return []string{}, nil
+		}
+		return nil, result.Err()
+	}
+	var user bson.M
+	if err := result.Decode(&user); err != nil {
+		return nil, err
+	}
+	roles := make([]string, 0)
+	if arr, ok := user[r.RolesName].(bson.A); ok {
+		for _, v := range arr {
+			if s, ok := v.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) AssignRole(ctx context.Context, userId string, roleId string) (int64, error) {
+	res, err := r.UserCollection.UpdateOne(ctx, bson.M{"_id": userId}, bson.M{"$addToSet": bson.M{r.RolesName: roleId}})
+	if err != nil {
+		return -1, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *RoleRepository) RevokeRole(ctx context.Context, userId string, roleId string) (int64, error) {
+	res, err := r.UserCollection.UpdateOne(ctx, bson.M{"_id": userId}, bson.M{"$pull": bson.M{r.RolesName: roleId}})
+	if err != nil {
+		return -1, err
+	}
+	return res.ModifiedCount, nil
+}
@@ -40,10 +40,13 @@ type MongoUserRepository struct {
 	AccessTimeFromName      string
 	AccessTimeToName        string
 	TwoFactorsName          string
+	TenantName              string
 }
 
 func NewUserRepositoryByConfig(db *mongo.Database, userCollectionName, passwordCollectionName string, activatedStatus interface{}, status a.UserStatusConfig, c a.SchemaConfig, options ...func(context.Context, string) (bool, error)) *MongoUserRepository {
-	return NewUserRepository(db, userCollectionName, passwordCollectionName, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.Roles, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r := NewUserRepository(db, userCollectionName, passwordCollectionName, activatedStatus, status, c.Username, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.Roles, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r.TenantName = c.Tenant
+	return r
 }
 func NewUserAdapterByConfig(db *mongo.Database, userCollectionName, passwordCollectionName string, activatedStatus interface{}, status a.UserStatusConfig, c a.SchemaConfig, options ...func(context.Context, string) (bool, error)) *MongoUserRepository {
 	return NewUserRepositoryByConfig(db, userCollectionName, passwordCollectionName, activatedStatus, status, c, options...)
@@ -67,6 +70,13 @@ func NewUserRepository(db *mongo.Database, userCollectionName, passwordCollectio
 func (r *MongoUserRepository) GetUser(ctx context.Context, username string) (*a.UserInfo, error) {
 	userInfo := a.UserInfo{}
 	query := bson.M{r.UserName: username}
+	if len(r.TenantName) > 0 {
+		tenant := a.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		query[r.TenantName] = tenant
+	}
 	result := r.UserCollection.FindOne(ctx, query)
 	if result.Err() != nil {
 		if fmt.Sprint(result.Err()) == "mongo: no documents in result" {
@@ -308,15 +318,26 @@ func (r *MongoUserRepository) Fail(ctx context.Context, userId string, failCount
 	}
 	if failCount != nil && len(r.FailCountName) > 0 {
 		pass[r.FailCountName] = *failCount + 1
-		if len(r.LockedUntilTimeName) > 0 {
-			pass[r.LockedUntilTimeName] = lockedUntil
-		}
+	}
+	if len(r.LockedUntilTimeName) > 0 && lockedUntil != nil {
+		pass[r.LockedUntilTimeName] = lockedUntil
 	}
 	query := bson.M{"_id": userId}
 	_, err := upsertOne(ctx, r.PasswordCollection, query, pass)
 	return err
 }
 
+func (r *MongoUserRepository) UpdatePassword(ctx context.Context, userId string, password string) (int64, error) {
+	if len(r.PasswordName) == 0 {
+		return 0, nil
+	}
+	result, err := r.PasswordCollection.UpdateOne(ctx, bson.M{"_id": userId}, bson.M{"$set": bson.M{r.PasswordName: password}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
 func exist(ctx context.Context, collection *mongo.Collection, id interface{}, objectId bool) (bool, error) {
 	query := bson.M{"_id": id}
 	if objectId {
@@ -17,6 +17,7 @@ type OAuth2UseCase struct {
 	PayloadConfig           auth.PayloadConfig
 	Privileges              func(ctx context.Context, id string) ([]auth.Privilege, error)
 	AccessTime              func(ctx context.Context, id string) (*auth.AccessTime, error)
+	Events                  auth.AuthEventSink
 }
 
 func NewOAuth2Service(status auth.Status, oauth2UserRepositories map[string]OAuth2UserRepository, userRepositories map[string]UserRepository, configurationRepository ConfigurationRepository, generate func(context.Context) (string, error), tokenService TokenPort, tokenConfig auth.TokenConfig, privileges func(context.Context, string) ([]auth.Privilege, error), options ...func(context.Context, string) (*auth.AccessTime, error)) *OAuth2UseCase {
@@ -77,10 +78,30 @@ func (s *OAuth2UseCase) Authenticate(ctx context.Context, info *OAuth2Info, auth
 			return result, nil
 		}
 		integrations.ClientId = clientId
-		return s.processAccount(ctx, info, *integrations, linkUserId)
+		result, er2 := s.processAccount(ctx, info, *integrations, linkUserId)
+		if s.Events != nil {
+			s.writeResult(ctx, info.Id, linkUserId, result, er2)
+		}
+		return result, er2
 	}
 	return result, nil
 }
+func (s *OAuth2UseCase) writeResult(ctx context.Context, provider string, linkUserId string, result auth.AuthResult, err error) {
+	event := auth.AuthEvent{Type: auth.EventLoginFailure, Provider: provider, Status: result.Status}
+	if result.User != nil {
+		event.UserId = result.User.Id
+		event.Username = result.User.Username
+	}
+	if err != nil {
+		event.Reason = err.Error()
+	} else if result.Status == s.Status.Success {
+		event.Type = auth.EventLoginSuccess
+		if len(linkUserId) > 0 {
+			event.Type = auth.EventOAuth2Linked
+		}
+	}
+	auth.WriteEvent(ctx, s.Events, event)
+}
 func (s *OAuth2UseCase) getStringValue(tokenData interface{}, field string) string {
 	if authorizationToken, ok := tokenData.(map[string]interface{}); ok {
 		value, _ := authorizationToken[field].(string)
@@ -0,0 +1,78 @@
+package password
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2Id = "argon2id"
+
+type Argon2Comparator struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  int
+	KeyLength   uint32
+}
+
+func NewArgon2Comparator(options ...uint32) *Argon2Comparator {
+	c := &Argon2Comparator{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+	if len(options) > 0 && options[0] > 0 {
+		c.Memory = options[0]
+	}
+	if len(options) > 1 && options[1] > 0 {
+		c.Iterations = options[1]
+	}
+	if len(options) > 2 && options[2] > 0 && options[2] <= 255 {
+		c.Parallelism = uint8(options[2])
+	}
+	return c
+}
+
+func (c *Argon2Comparator) Compare(plaintext string, hashed string) (bool, error) {
+	p, err := parsePHC(hashed)
+	if err != nil || p.Id != argon2Id {
+		return false, ErrInvalidHash
+	}
+	if p.Version != 0 && p.Version != argon2.Version {
+		return false, ErrInvalidHash
+	}
+	m, er1 := p.Int("m")
+	t, er2 := p.Int("t")
+	l, er3 := p.Int("p")
+	if er1 != nil || er2 != nil || er3 != nil || l > 255 {
+		return false, ErrInvalidHash
+	}
+	key := argon2.IDKey([]byte(plaintext), p.Salt, uint32(t), uint32(m), uint8(l), uint32(len(p.Hash)))
+	return subtle.ConstantTimeCompare(key, p.Hash) == 1, nil
+}
+
+func (c *Argon2Comparator) Hash(plaintext string) (string, error) {
+	salt, err := newSalt(c.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plaintext), salt, c.Iterations, c.Memory, c.Parallelism, c.KeyLength)
+	params := fmt.Sprintf("m=%d,t=%d,p=%d", c.Memory, c.Iterations, c.Parallelism)
+	return formatPHC(argon2Id, argon2.Version, params, salt, key), nil
+}
+
+func (c *Argon2Comparator) Supports(hashed string) bool {
+	return phcId(hashed) == argon2Id
+}
+
+func (c *Argon2Comparator) NeedsRehash(hashed string) bool {
+	p, err := parsePHC(hashed)
+	if err != nil || p.Id != argon2Id || p.Version != argon2.Version {
+		return true
+	}
+	m, er1 := p.Int("m")
+	t, er2 := p.Int("t")
+	l, er3 := p.Int("p")
+	if er1 != nil || er2 != nil || er3 != nil {
+		return true
+	}
+	return uint32(m) < c.Memory || uint32(t) < c.Iterations || l < int(c.Parallelism) || uint32(len(p.Hash)) < c.KeyLength
+}
@@ -0,0 +1,46 @@
+package password
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+type BcryptComparator struct {
+	Cost int
+}
+
+func NewBcryptComparator(options ...int) *BcryptComparator {
+	cost := 12
+	if len(options) > 0 && options[0] >= bcrypt.MinCost && options[0] <= bcrypt.MaxCost {
+		cost = options[0]
+	}
+	return &BcryptComparator{Cost: cost}
+}
+
+func (c *BcryptComparator) Compare(plaintext string, hashed string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plaintext))
+	if err == nil {
+		return true, nil
+	}
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return false, err
+}
+
+func (c *BcryptComparator) Hash(plaintext string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plaintext), c.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (c *BcryptComparator) Supports(hashed string) bool {
+	id := phcId(hashed)
+	return id == "2a" || id == "2b" || id == "2y"
+}
+
+func (c *BcryptComparator) NeedsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost < c.Cost
+}
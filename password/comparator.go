@@ -0,0 +1,51 @@
+package password
+
+import "errors"
+
+type Comparator struct {
+	Default Hasher
+	Hashers []Hasher
+}
+
+func NewComparator(defaultHasher Hasher, hashers ...Hasher) *Comparator {
+	if defaultHasher == nil {
+		panic(errors.New("default hasher cannot be nil"))
+	}
+	return &Comparator{Default: defaultHasher, Hashers: append([]Hasher{defaultHasher}, hashers...)}
+}
+
+func NewDefaultComparator() *Comparator {
+	return NewComparator(NewArgon2Comparator(), NewBcryptComparator(), NewScryptComparator(), NewPBKDF2Comparator("sha256"), NewPBKDF2Comparator("sha512"))
+}
+
+func (c *Comparator) Compare(plaintext string, hashed string) (bool, error) {
+	hasher := c.detect(hashed)
+	if hasher == nil {
+		return false, ErrInvalidHash
+	}
+	return hasher.Compare(plaintext, hashed)
+}
+
+func (c *Comparator) Hash(plaintext string) (string, error) {
+	return c.Default.Hash(plaintext)
+}
+
+func (c *Comparator) Supports(hashed string) bool {
+	return c.detect(hashed) != nil
+}
+
+func (c *Comparator) NeedsRehash(hashed string) bool {
+	if !c.Default.Supports(hashed) {
+		return true
+	}
+	return c.Default.NeedsRehash(hashed)
+}
+
+func (c *Comparator) detect(hashed string) Hasher {
+	for _, hasher := range c.Hashers {
+		if hasher.Supports(hashed) {
+			return hasher
+		}
+	}
+	return nil
+}
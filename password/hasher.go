@@ -0,0 +1,9 @@
+package password
+
+import auth "github.com/core-go/authentication"
+
+type Hasher interface {
+	auth.ValueComparator
+	Supports(hashed string) bool
+	NeedsRehash(hashed string) bool
+}
@@ -0,0 +1,84 @@
+package password
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"hash"
+	"strconv"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+type PBKDF2Comparator struct {
+	Algorithm  string
+	Iterations int
+	SaltLength int
+	KeyLength  int
+}
+
+func NewPBKDF2Comparator(algorithm string, options ...int) *PBKDF2Comparator {
+	if pbkdf2Hash(algorithm) == nil {
+		algorithm = "sha256"
+	}
+	c := &PBKDF2Comparator{Algorithm: algorithm, Iterations: 600000, SaltLength: 16, KeyLength: 32}
+	if algorithm == "sha512" {
+		c.Iterations = 210000
+		c.KeyLength = 64
+	}
+	if len(options) > 0 && options[0] > 0 {
+		c.Iterations = options[0]
+	}
+	return c
+}
+
+func (c *PBKDF2Comparator) Compare(plaintext string, hashed string) (bool, error) {
+	p, err := parsePHC(hashed)
+	if err != nil || len(p.Id) <= 7 || p.Id[:7] != "pbkdf2-" {
+		return false, ErrInvalidHash
+	}
+	h := pbkdf2Hash(p.Id[7:])
+	i, er1 := p.Int("i")
+	if h == nil || er1 != nil {
+		return false, ErrInvalidHash
+	}
+	key := pbkdf2.Key([]byte(plaintext), p.Salt, i, len(p.Hash), h)
+	return subtle.ConstantTimeCompare(key, p.Hash) == 1, nil
+}
+
+func (c *PBKDF2Comparator) Hash(plaintext string) (string, error) {
+	salt, err := newSalt(c.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key := pbkdf2.Key([]byte(plaintext), salt, c.Iterations, c.KeyLength, pbkdf2Hash(c.Algorithm))
+	return formatPHC("pbkdf2-"+c.Algorithm, 0, "i="+strconv.Itoa(c.Iterations), salt, key), nil
+}
+
+func (c *PBKDF2Comparator) Supports(hashed string) bool {
+	id := phcId(hashed)
+	return len(id) > 7 && id[:7] == "pbkdf2-" && pbkdf2Hash(id[7:]) != nil
+}
+
+func (c *PBKDF2Comparator) NeedsRehash(hashed string) bool {
+	p, err := parsePHC(hashed)
+	if err != nil || p.Id != "pbkdf2-"+c.Algorithm {
+		return true
+	}
+	i, err := p.Int("i")
+	return err != nil || i < c.Iterations || len(p.Hash) < c.KeyLength
+}
+
+func pbkdf2Hash(algorithm string) func() hash.Hash {
+	switch algorithm {
+	case "sha1":
+		return sha1.New
+	case "sha256":
+		return sha256.New
+	case "sha512":
+		return sha512.New
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package password
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/core-go/authentication/random"
+)
+
+var ErrInvalidHash = errors.New("invalid hash format")
+
+type phc struct {
+	Id      string
+	Version int
+	Params  map[string]string
+	Salt    []byte
+	Hash    []byte
+}
+
+func parsePHC(hashed string) (*phc, error) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) < 4 || len(parts[0]) > 0 {
+		return nil, ErrInvalidHash
+	}
+	p := &phc{Id: parts[1], Params: make(map[string]string)}
+	i := 2
+	if strings.HasPrefix(parts[i], "v=") {
+		v, err := strconv.Atoi(parts[i][2:])
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		p.Version = v
+		i++
+	}
+	if len(parts) != i+3 {
+		return nil, ErrInvalidHash
+	}
+	for _, param := range strings.Split(parts[i], ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrInvalidHash
+		}
+		p.Params[kv[0]] = kv[1]
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[i+1])
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[i+2])
+	if err != nil || len(hash) == 0 {
+		return nil, ErrInvalidHash
+	}
+	p.Salt = salt
+	p.Hash = hash
+	return p, nil
+}
+
+func (p *phc) Int(key string) (int, error) {
+	v, ok := p.Params[key]
+	if !ok {
+		return 0, ErrInvalidHash
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i <= 0 {
+		return 0, ErrInvalidHash
+	}
+	return i, nil
+}
+
+func formatPHC(id string, version int, params string, salt []byte, hash []byte) string {
+	s := "$" + id
+	if version > 0 {
+		s = s + "$v=" + strconv.Itoa(version)
+	}
+	return s + "$" + params + "$" + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(hash)
+}
+
+func phcId(hashed string) string {
+	if !strings.HasPrefix(hashed, "$") {
+		return ""
+	}
+	parts := strings.SplitN(hashed[1:], "$", 2)
+	return parts[0]
+}
+
+func newSalt(size int) ([]byte, error) {
+	return random.Bytes(size)
+}
@@ -0,0 +1,81 @@
+package password
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const scryptId = "scrypt"
+
+type ScryptComparator struct {
+	LogN       int
+	R          int
+	P          int
+	SaltLength int
+	KeyLength  int
+}
+
+func NewScryptComparator(options ...int) *ScryptComparator {
+	c := &ScryptComparator{LogN: 15, R: 8, P: 1, SaltLength: 16, KeyLength: 32}
+	if len(options) > 0 && options[0] > 0 && options[0] < 31 {
+		c.LogN = options[0]
+	}
+	if len(options) > 1 && options[1] > 0 {
+		c.R = options[1]
+	}
+	if len(options) > 2 && options[2] > 0 {
+		c.P = options[2]
+	}
+	return c
+}
+
+func (c *ScryptComparator) Compare(plaintext string, hashed string) (bool, error) {
+	p, err := parsePHC(hashed)
+	if err != nil || p.Id != scryptId {
+		return false, ErrInvalidHash
+	}
+	ln, er1 := p.Int("ln")
+	r, er2 := p.Int("r")
+	l, er3 := p.Int("p")
+	if er1 != nil || er2 != nil || er3 != nil || ln >= 31 {
+		return false, ErrInvalidHash
+	}
+	key, err := scrypt.Key([]byte(plaintext), p.Salt, 1<<uint(ln), r, l, len(p.Hash))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, p.Hash) == 1, nil
+}
+
+func (c *ScryptComparator) Hash(plaintext string) (string, error) {
+	salt, err := newSalt(c.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(plaintext), salt, 1<<uint(c.LogN), c.R, c.P, c.KeyLength)
+	if err != nil {
+		return "", err
+	}
+	params := fmt.Sprintf("ln=%d,r=%d,p=%d", c.LogN, c.R, c.P)
+	return formatPHC(scryptId, 0, params, salt, key), nil
+}
+
+func (c *ScryptComparator) Supports(hashed string) bool {
+	return phcId(hashed) == scryptId
+}
+
+func (c *ScryptComparator) NeedsRehash(hashed string) bool {
+	p, err := parsePHC(hashed)
+	if err != nil || p.Id != scryptId {
+		return true
+	}
+	ln, er1 := p.Int("ln")
+	r, er2 := p.Int("r")
+	l, er3 := p.Int("p")
+	if er1 != nil || er2 != nil || er3 != nil {
+		return true
+	}
+	return ln < c.LogN || r < c.R || l < c.P || len(p.Hash) < c.KeyLength
+}
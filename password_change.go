@@ -0,0 +1,16 @@
+package auth
+
+const (
+	PasswordFailed  = 0
+	PasswordChanged = 1
+	PasswordReused  = 2
+	PasswordInvalid = 3
+	PasswordLocked  = 4
+)
+
+type PasswordChange struct {
+	Id              string `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Username        string `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	CurrentPassword string `yaml:"current_password" mapstructure:"current_password" json:"currentPassword,omitempty" gorm:"column:currentpassword" bson:"currentPassword,omitempty" dynamodbav:"currentPassword,omitempty" firestore:"currentPassword,omitempty"`
+	Password        string `yaml:"password" mapstructure:"password" json:"password,omitempty" gorm:"column:password" bson:"password,omitempty" dynamodbav:"password,omitempty" firestore:"password,omitempty"`
+}
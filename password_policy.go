@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+)
+
+type PasswordPolicy struct {
+	Config   PasswordPolicyConfig
+	Banned   []string
+	Breached BreachedPasswordChecker
+}
+
+func NewPasswordPolicy(conf PasswordPolicyConfig, options ...BreachedPasswordChecker) (*PasswordPolicy, error) {
+	banned := make([]string, 0, len(conf.BannedWords))
+	for _, word := range conf.BannedWords {
+		if w := strings.ToLower(strings.TrimSpace(word)); len(w) > 0 {
+			banned = append(banned, w)
+		}
+	}
+	if len(conf.BannedFile) > 0 {
+		words, err := readLines(conf.BannedFile)
+		if err != nil {
+			return nil, err
+		}
+		banned = append(banned, words...)
+	}
+	var breached BreachedPasswordChecker
+	if len(options) > 0 && options[0] != nil {
+		breached = options[0]
+	} else if len(conf.BreachedFile) > 0 {
+		breached = NewHashPrefixChecker(conf.BreachedFile, conf.BreachedMin)
+	}
+	return &PasswordPolicy{Config: conf, Banned: banned, Breached: breached}, nil
+}
+
+func (p *PasswordPolicy) Validate(ctx context.Context, password string, username string, displayName string) ([]PasswordViolation, error) {
+	violations := make([]PasswordViolation, 0)
+	c := p.Config
+	length := len([]rune(password))
+	if c.MinLength > 0 && length < c.MinLength {
+		violations = append(violations, PasswordViolation{Code: ViolationMinLength, Param: c.MinLength, Message: fmt.Sprintf("password must be at least %d characters", c.MinLength)})
+	}
+	if c.MaxLength > 0 && length > c.MaxLength {
+		violations = append(violations, PasswordViolation{Code: ViolationMaxLength, Param: c.MaxLength, Message: fmt.Sprintf("password must be at most %d characters", c.MaxLength)})
+	}
+	var upper, lower, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsDigit(r):
+			digit++
+		default:
+			symbol++
+		}
+	}
+	if upper < c.Uppercase {
+		violations = append(violations, PasswordViolation{Code: ViolationUppercase, Param: c.Uppercase, Message: fmt.Sprintf("password must contain at least %d uppercase letters", c.Uppercase)})
+	}
+	if lower < c.Lowercase {
+		violations = append(violations, PasswordViolation{Code: ViolationLowercase, Param: c.Lowercase, Message: fmt.Sprintf("password must contain at least %d lowercase letters", c.Lowercase)})
+	}
+	if digit < c.Digit {
+		violations = append(violations, PasswordViolation{Code: ViolationDigit, Param: c.Digit, Message: fmt.Sprintf("password must contain at least %d digits", c.Digit)})
+	}
+	if symbol < c.Symbol {
+		violations = append(violations, PasswordViolation{Code: ViolationSymbol, Param: c.Symbol, Message: fmt.Sprintf("password must contain at least %d symbols", c.Symbol)})
+	}
+	lowerPassword := strings.ToLower(password)
+	for _, word := range p.Banned {
+		if strings.Contains(lowerPassword, word) {
+			violations = append(violations, PasswordViolation{Code: ViolationBanned, Param: word, Message: "password contains a banned word"})
+			break
+		}
+	}
+	if c.Similarity > 0 {
+		values := []string{username}
+		values = append(values, strings.Fields(displayName)...)
+		for _, value := range values {
+			if isSimilar(lowerPassword, strings.ToLower(value), c.Similarity) {
+				violations = append(violations, PasswordViolation{Code: ViolationSimilar, Message: "password is too similar to the username or display name"})
+				break
+			}
+		}
+	}
+	if c.MinEntropy > 0 {
+		if entropy := PasswordEntropy(password); entropy < c.MinEntropy {
+			violations = append(violations, PasswordViolation{Code: ViolationEntropy, Param: c.MinEntropy, Message: "password is too easy to guess"})
+		}
+	}
+	if p.Breached != nil && len(password) > 0 {
+		breached, err := p.Breached.IsBreached(ctx, password)
+		if err != nil {
+			return violations, err
+		}
+		if breached {
+			violations = append(violations, PasswordViolation{Code: ViolationBreached, Message: "password has appeared in a data breach"})
+		}
+	}
+	return violations, nil
+}
+
+func PasswordEntropy(password string) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+	var hasUpper, hasLower, hasDigit, hasSymbol, hasOther bool
+	unique := make(map[rune]bool)
+	for _, r := range runes {
+		unique[r] = true
+		switch {
+		case r > unicode.MaxASCII:
+			hasOther = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+	pool := 0
+	if hasLower {
+		pool += 26
+	}
+	if hasUpper {
+		pool += 26
+	}
+	if hasDigit {
+		pool += 10
+	}
+	if hasSymbol {
+		pool += 33
+	}
+	if hasOther {
+		pool += 100
+	}
+	effective := float64(len(runes)+len(unique)) / 2
+	return effective * math.Log2(float64(pool))
+}
+
+func isSimilar(password string, value string, threshold float64) bool {
+	if len([]rune(value)) < 3 {
+		return false
+	}
+	if strings.Contains(password, value) {
+		return true
+	}
+	a, b := []rune(password), []rune(value)
+	max := len(a)
+	if len(b) > max {
+		max = len(b)
+	}
+	return 1-float64(levenshtein(a, b))/float64(max) >= threshold
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func readLines(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.ToLower(strings.TrimSpace(scanner.Text())); len(line) > 0 && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
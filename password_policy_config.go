@@ -0,0 +1,16 @@
+package auth
+
+type PasswordPolicyConfig struct {
+	MinLength    int      `yaml:"min_length" mapstructure:"min_length" json:"minLength,omitempty" gorm:"column:minlength" bson:"minLength,omitempty" dynamodbav:"minLength,omitempty" firestore:"minLength,omitempty"`
+	MaxLength    int      `yaml:"max_length" mapstructure:"max_length" json:"maxLength,omitempty" gorm:"column:maxlength" bson:"maxLength,omitempty" dynamodbav:"maxLength,omitempty" firestore:"maxLength,omitempty"`
+	Uppercase    int      `yaml:"uppercase" mapstructure:"uppercase" json:"uppercase,omitempty" gorm:"column:uppercase" bson:"uppercase,omitempty" dynamodbav:"uppercase,omitempty" firestore:"uppercase,omitempty"`
+	Lowercase    int      `yaml:"lowercase" mapstructure:"lowercase" json:"lowercase,omitempty" gorm:"column:lowercase" bson:"lowercase,omitempty" dynamodbav:"lowercase,omitempty" firestore:"lowercase,omitempty"`
+	Digit        int      `yaml:"digit" mapstructure:"digit" json:"digit,omitempty" gorm:"column:digit" bson:"digit,omitempty" dynamodbav:"digit,omitempty" firestore:"digit,omitempty"`
+	Symbol       int      `yaml:"symbol" mapstructure:"symbol" json:"symbol,omitempty" gorm:"column:symbol" bson:"symbol,omitempty" dynamodbav:"symbol,omitempty" firestore:"symbol,omitempty"`
+	BannedWords  []string `yaml:"banned_words" mapstructure:"banned_words" json:"bannedWords,omitempty" gorm:"column:bannedwords" bson:"bannedWords,omitempty" dynamodbav:"bannedWords,omitempty" firestore:"bannedWords,omitempty"`
+	BannedFile   string   `yaml:"banned_file" mapstructure:"banned_file" json:"bannedFile,omitempty" gorm:"column:bannedfile" bson:"bannedFile,omitempty" dynamodbav:"bannedFile,omitempty" firestore:"bannedFile,omitempty"`
+	Similarity   float64  `yaml:"similarity" mapstructure:"similarity" json:"similarity,omitempty" gorm:"column:similarity" bson:"similarity,omitempty" dynamodbav:"similarity,omitempty" firestore:"similarity,omitempty"`
+	MinEntropy   float64  `yaml:"min_entropy" mapstructure:"min_entropy" json:"minEntropy,omitempty" gorm:"column:minentropy" bson:"minEntropy,omitempty" dynamodbav:"minEntropy,omitempty" firestore:"minEntropy,omitempty"`
+	BreachedFile string   `yaml:"breached_file" mapstructure:"breached_file" json:"breachedFile,omitempty" gorm:"column:breachedfile" bson:"breachedFile,omitempty" dynamodbav:"breachedFile,omitempty" firestore:"breachedFile,omitempty"`
+	BreachedMin  int64    `yaml:"breached_min" mapstructure:"breached_min" json:"breachedMin,omitempty" gorm:"column:breachedmin" bson:"breachedMin,omitempty" dynamodbav:"breachedMin,omitempty" firestore:"breachedMin,omitempty"`
+}
@@ -0,0 +1,5 @@
+package auth
+
+type PasswordRehasher interface {
+	NeedsRehash(hashed string) bool
+}
@@ -0,0 +1,9 @@
+package auth
+
+import "context"
+
+type PasswordRepository interface {
+	GetUserByContact(ctx context.Context, contact string) (*UserInfo, error)
+	GetHistory(ctx context.Context, id string, max int) ([]string, error)
+	Update(ctx context.Context, id string, newPassword string) (int64, error)
+}
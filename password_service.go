@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/core-go/authentication/random"
+)
+
+// resetPrefix keeps reset tokens apart from the two-factor passcodes when both share a CodeRepository.
+const resetPrefix = "reset:"
+
+type PasswordService struct {
+	Repository     PasswordRepository
+	Comparator     ValueComparator
+	CodeRepository CodeRepository
+	SendCode       func(ctx context.Context, to string, code string, expireAt time.Time, params interface{}) error
+	Secret         string
+	Expires        int64
+	HistoryCount   int
+	Policy         *PasswordPolicy
+	// Authenticator, when set, applies the login lock check and fail/lockout path to a wrong current password.
+	Authenticator *Authenticator
+}
+
+func NewPasswordService(repository PasswordRepository, comparator ValueComparator, codeRepository CodeRepository, sendCode func(context.Context, string, string, time.Time, interface{}) error, secret string, expires int64, options ...int) *PasswordService {
+	if repository == nil || comparator == nil {
+		panic(errors.New("password repository and comparator cannot be nil"))
+	}
+	if sendCode != nil && (codeRepository == nil || len(secret) == 0 || expires <= 0) {
+		panic(errors.New("when using password reset, codeRepository and secret must not be empty, and expires must be greater than 0"))
+	}
+	historyCount := 0
+	if len(options) > 0 && options[0] > 0 {
+		historyCount = options[0]
+	}
+	return &PasswordService{Repository: repository, Comparator: comparator, CodeRepository: codeRepository, SendCode: sendCode, Secret: secret, Expires: expires, HistoryCount: historyCount}
+}
+
+func (s *PasswordService) ChangePassword(ctx context.Context, pass PasswordChange) (int, error) {
+	if len(pass.Username) == 0 || len(pass.CurrentPassword) == 0 || len(pass.Password) == 0 {
+		return PasswordFailed, nil
+	}
+	user, err := s.Repository.GetUserByContact(ctx, pass.Username)
+	if err != nil || user == nil {
+		return PasswordFailed, err
+	}
+	// pass.Id is the authenticated user; the handlers set it from the request context
+	if len(pass.Id) > 0 && pass.Id != user.Id {
+		return PasswordFailed, nil
+	}
+	var account *UserInfo
+	if s.Authenticator != nil && s.Authenticator.Repository != nil {
+		account, err = s.Authenticator.Repository.GetUser(ctx, user.Username)
+		if err != nil {
+			return PasswordFailed, err
+		}
+		if account != nil && account.LockedUntilTime != nil && compareDate(time.Now(), *account.LockedUntilTime) < 0 {
+			return PasswordLocked, nil
+		}
+	}
+	valid, err := s.Comparator.Compare(pass.CurrentPassword, user.Password)
+	if err != nil {
+		return PasswordFailed, err
+	}
+	if !valid {
+		if account != nil {
+			if er2 := s.Authenticator.fail(ctx, AuthInfo{Username: user.Username}, *account); er2 != nil {
+				return PasswordFailed, er2
+			}
+		}
+		return PasswordFailed, nil
+	}
+	displayName := ""
+	if user.DisplayName != nil {
+		displayName = *user.DisplayName
+	}
+	if result, err := s.validate(ctx, pass.Password, user.Username, displayName); err != nil {
+		return result, err
+	}
+	return s.update(ctx, user.Id, pass.Password, user.Password)
+}
+
+func (s *PasswordService) ForgotPassword(ctx context.Context, contact string) (bool, error) {
+	if s.SendCode == nil {
+		return false, errors.New("password reset is not configured")
+	}
+	if len(strings.TrimSpace(contact)) == 0 {
+		return false, nil
+	}
+	user, err := s.Repository.GetUserByContact(ctx, contact)
+	if err != nil || user == nil {
+		return false, err
+	}
+	code, err := random.Token(32)
+	if err != nil {
+		return false, err
+	}
+	expiredAt := addSeconds(time.Now(), s.Expires)
+	displayName := ""
+	if user.DisplayName != nil {
+		displayName = *user.DisplayName
+	}
+	count, err := s.CodeRepository.Save(ctx, resetPrefix+user.Id, joinReset(s.sign(code), user.Username, displayName), expiredAt)
+	if err != nil || count <= 0 {
+		return false, err
+	}
+	token := s.buildToken(user.Id, code, expiredAt)
+	to := contact
+	if user.Email != nil && len(*user.Email) > 0 {
+		to = *user.Email
+	} else if user.Contact != nil && len(*user.Contact) > 0 {
+		to = *user.Contact
+	}
+	if err = s.SendCode(ctx, to, token, expiredAt, user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResetPassword returns the username the token was issued for, so the caller never trusts the one sent by the client.
+func (s *PasswordService) ResetPassword(ctx context.Context, pass PasswordReset) (int, string, error) {
+	if s.CodeRepository == nil || len(pass.Password) == 0 {
+		return PasswordFailed, "", nil
+	}
+	id, code, ok := s.parseToken(pass.Passcode)
+	if !ok {
+		return PasswordFailed, "", nil
+	}
+	stored, expiredAt, err := s.CodeRepository.Load(ctx, resetPrefix+id)
+	if err != nil || len(stored) == 0 {
+		return PasswordFailed, "", err
+	}
+	signature, username, displayName := splitReset(stored)
+	if compareDate(expiredAt, time.Now()) < 0 || !hmac.Equal([]byte(signature), []byte(s.sign(code))) {
+		_, err = s.CodeRepository.Delete(ctx, resetPrefix+id)
+		return PasswordFailed, "", err
+	}
+	// the policy checks the user the token was issued for, never the username sent by the client
+	if result, err := s.validate(ctx, pass.Password, username, displayName); err != nil {
+		return result, "", err
+	}
+	if _, err = s.CodeRepository.Delete(ctx, resetPrefix+id); err != nil {
+		return PasswordFailed, "", err
+	}
+	result, err := s.update(ctx, id, pass.Password, "")
+	return result, username, err
+}
+
+func (s *PasswordService) validate(ctx context.Context, password string, username string, displayName string) (int, error) {
+	if s.Policy == nil {
+		return PasswordChanged, nil
+	}
+	violations, err := s.Policy.Validate(ctx, password, username, displayName)
+	if err != nil {
+		return PasswordFailed, err
+	}
+	if len(violations) > 0 {
+		return PasswordInvalid, PasswordViolations(violations)
+	}
+	return PasswordChanged, nil
+}
+
+func (s *PasswordService) update(ctx context.Context, id string, password string, current string) (int, error) {
+	reused, err := s.isReused(ctx, id, password, current)
+	if err != nil || reused {
+		return PasswordReused, err
+	}
+	hashed, err := s.Comparator.Hash(password)
+	if err != nil {
+		return PasswordFailed, err
+	}
+	count, err := s.Repository.Update(ctx, id, hashed)
+	if err != nil || count <= 0 {
+		return PasswordFailed, err
+	}
+	return PasswordChanged, nil
+}
+
+func (s *PasswordService) isReused(ctx context.Context, id string, password string, current string) (bool, error) {
+	hashes := make([]string, 0)
+	if len(current) > 0 {
+		hashes = append(hashes, current)
+	}
+	if s.HistoryCount > 0 {
+		history, err := s.Repository.GetHistory(ctx, id, s.HistoryCount)
+		if err != nil {
+			return false, err
+		}
+		hashes = append(hashes, history...)
+	}
+	for _, hash := range hashes {
+		used, err := s.Comparator.Compare(password, hash)
+		if err != nil {
+			return false, err
+		}
+		if used {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *PasswordService) buildToken(id string, code string, expiredAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(id)) + "." + strconv.FormatInt(expiredAt.Unix(), 10) + "." + code
+	return payload + "." + s.sign(payload)
+}
+
+func (s *PasswordService) parseToken(token string) (string, string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return "", "", false
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.sign(payload))) {
+		return "", "", false
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return "", "", false
+	}
+	id, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(id) == 0 {
+		return "", "", false
+	}
+	return string(id), parts[2], true
+}
+
+// joinReset stores the user the reset token was issued for along with the signature of its code.
+func joinReset(signature string, username string, displayName string) string {
+	return signature + "." + base64.RawURLEncoding.EncodeToString([]byte(username)) + "." + base64.RawURLEncoding.EncodeToString([]byte(displayName))
+}
+
+func splitReset(stored string) (string, string, string) {
+	parts := strings.Split(stored, ".")
+	if len(parts) != 3 {
+		return stored, "", ""
+	}
+	username, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	displayName, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	return parts[0], string(username), string(displayName)
+}
+
+func (s *PasswordService) sign(value string) string {
+	h := hmac.New(sha256.New, []byte(s.Secret))
+	h.Write([]byte(value))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,7 @@
+package auth
+
+import "context"
+
+type PasswordUpdater interface {
+	UpdatePassword(ctx context.Context, id string, password string) (int64, error)
+}
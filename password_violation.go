@@ -0,0 +1,32 @@
+package auth
+
+import "strings"
+
+const (
+	ViolationMinLength = "min_length"
+	ViolationMaxLength = "max_length"
+	ViolationUppercase = "uppercase"
+	ViolationLowercase = "lowercase"
+	ViolationDigit     = "digit"
+	ViolationSymbol    = "symbol"
+	ViolationBanned    = "banned"
+	ViolationSimilar   = "similar"
+	ViolationEntropy   = "entropy"
+	ViolationBreached  = "breached"
+)
+
+type PasswordViolation struct {
+	Code    string      `yaml:"code" mapstructure:"code" json:"code,omitempty" gorm:"column:code" bson:"code,omitempty" dynamodbav:"code,omitempty" firestore:"code,omitempty"`
+	Param   interface{} `yaml:"param" mapstructure:"param" json:"param,omitempty" gorm:"column:param" bson:"param,omitempty" dynamodbav:"param,omitempty" firestore:"param,omitempty"`
+	Message string      `yaml:"message" mapstructure:"message" json:"message,omitempty" gorm:"column:message" bson:"message,omitempty" dynamodbav:"message,omitempty" firestore:"message,omitempty"`
+}
+
+type PasswordViolations []PasswordViolation
+
+func (v PasswordViolations) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, violation := range v {
+		messages = append(messages, violation.Message)
+	}
+	return "password policy: " + strings.Join(messages, "; ")
+}
@@ -12,4 +12,5 @@ type PayloadConfig struct {
 	Roles      string `yaml:"roles" mapstructure:"roles" json:"roles,omitempty" gorm:"column:roles" bson:"roles,omitempty" dynamodbav:"roles,omitempty" firestore:"roles,omitempty"`
 	Privileges string `yaml:"privileges" mapstructure:"privileges" json:"privileges,omitempty" gorm:"column:privileges" bson:"privileges,omitempty" dynamodbav:"privileges,omitempty" firestore:"privileges,omitempty"`
 	Tokens     string `yaml:"tokens" mapstructure:"tokens" json:"tokens,omitempty" gorm:"column:tokens" bson:"tokens,omitempty" dynamodbav:"tokens,omitempty" firestore:"tokens,omitempty"`
+	Tenant     string `yaml:"tenant" mapstructure:"tenant" json:"tenant,omitempty" gorm:"column:tenant" bson:"tenant,omitempty" dynamodbav:"tenant,omitempty" firestore:"tenant,omitempty"`
 }
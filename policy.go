@@ -0,0 +1,39 @@
+package auth
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+type Policy struct {
+	Id          string      `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Description string      `yaml:"description" mapstructure:"description" json:"description,omitempty" gorm:"column:description" bson:"description,omitempty" dynamodbav:"description,omitempty" firestore:"description,omitempty"`
+	Effect      string      `yaml:"effect" mapstructure:"effect" json:"effect,omitempty" gorm:"column:effect" bson:"effect,omitempty" dynamodbav:"effect,omitempty" firestore:"effect,omitempty"`
+	Actions     []string    `yaml:"actions" mapstructure:"actions" json:"actions,omitempty" gorm:"column:actions" bson:"actions,omitempty" dynamodbav:"actions,omitempty" firestore:"actions,omitempty"`
+	Resources   []string    `yaml:"resources" mapstructure:"resources" json:"resources,omitempty" gorm:"column:resources" bson:"resources,omitempty" dynamodbav:"resources,omitempty" firestore:"resources,omitempty"`
+	Conditions  []Condition `yaml:"conditions" mapstructure:"conditions" json:"conditions,omitempty" gorm:"column:conditions" bson:"conditions,omitempty" dynamodbav:"conditions,omitempty" firestore:"conditions,omitempty"`
+	Time        *PolicyTime `yaml:"time" mapstructure:"time" json:"time,omitempty" gorm:"column:time" bson:"time,omitempty" dynamodbav:"time,omitempty" firestore:"time,omitempty"`
+}
+
+type Condition struct {
+	Attribute string      `yaml:"attribute" mapstructure:"attribute" json:"attribute,omitempty" gorm:"column:attribute" bson:"attribute,omitempty" dynamodbav:"attribute,omitempty" firestore:"attribute,omitempty"`
+	Operator  string      `yaml:"operator" mapstructure:"operator" json:"operator,omitempty" gorm:"column:operator" bson:"operator,omitempty" dynamodbav:"operator,omitempty" firestore:"operator,omitempty"`
+	Value     interface{} `yaml:"value" mapstructure:"value" json:"value,omitempty" gorm:"column:value" bson:"value,omitempty" dynamodbav:"value,omitempty" firestore:"value,omitempty"`
+	Ref       string      `yaml:"ref" mapstructure:"ref" json:"ref,omitempty" gorm:"column:ref" bson:"ref,omitempty" dynamodbav:"ref,omitempty" firestore:"ref,omitempty"`
+}
+
+// PolicyTime uses the same rules as IsAccessDateValid and IsAccessTimeValid; dates are "2006-01-02", times are "15:04".
+type PolicyTime struct {
+	DateFrom string `yaml:"date_from" mapstructure:"date_from" json:"dateFrom,omitempty" gorm:"column:datefrom" bson:"dateFrom,omitempty" dynamodbav:"dateFrom,omitempty" firestore:"dateFrom,omitempty"`
+	DateTo   string `yaml:"date_to" mapstructure:"date_to" json:"dateTo,omitempty" gorm:"column:dateto" bson:"dateTo,omitempty" dynamodbav:"dateTo,omitempty" firestore:"dateTo,omitempty"`
+	TimeFrom string `yaml:"time_from" mapstructure:"time_from" json:"timeFrom,omitempty" gorm:"column:timefrom" bson:"timeFrom,omitempty" dynamodbav:"timeFrom,omitempty" firestore:"timeFrom,omitempty"`
+	TimeTo   string `yaml:"time_to" mapstructure:"time_to" json:"timeTo,omitempty" gorm:"column:timeto" bson:"timeTo,omitempty" dynamodbav:"timeTo,omitempty" firestore:"timeTo,omitempty"`
+}
+
+type AccessRequest struct {
+	Action       string                 `yaml:"action" mapstructure:"action" json:"action,omitempty" gorm:"column:action" bson:"action,omitempty" dynamodbav:"action,omitempty" firestore:"action,omitempty"`
+	ResourceType string                 `yaml:"resource_type" mapstructure:"resource_type" json:"resourceType,omitempty" gorm:"column:resourcetype" bson:"resourceType,omitempty" dynamodbav:"resourceType,omitempty" firestore:"resourceType,omitempty"`
+	Subject      map[string]interface{} `yaml:"subject" mapstructure:"subject" json:"subject,omitempty" gorm:"column:subject" bson:"subject,omitempty" dynamodbav:"subject,omitempty" firestore:"subject,omitempty"`
+	Resource     map[string]interface{} `yaml:"resource" mapstructure:"resource" json:"resource,omitempty" gorm:"column:resource" bson:"resource,omitempty" dynamodbav:"resource,omitempty" firestore:"resource,omitempty"`
+	Environment  map[string]interface{} `yaml:"environment" mapstructure:"environment" json:"environment,omitempty" gorm:"column:environment" bson:"environment,omitempty" dynamodbav:"environment,omitempty" firestore:"environment,omitempty"`
+}
@@ -0,0 +1,429 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	OperatorEqual        = "eq"
+	OperatorNotEqual     = "ne"
+	OperatorIn           = "in"
+	OperatorNotIn        = "not_in"
+	OperatorContains     = "contains"
+	OperatorGreater      = "gt"
+	OperatorGreaterEqual = "gte"
+	OperatorLess         = "lt"
+	OperatorLessEqual    = "lte"
+	OperatorExists       = "exists"
+	OperatorCIDR         = "cidr"
+)
+
+type PolicyEngine struct {
+	Policies []Policy
+}
+
+func NewPolicyEngine(policies []Policy) (*PolicyEngine, error) {
+	for _, p := range policies {
+		if p.Effect != EffectAllow && p.Effect != EffectDeny {
+			return nil, fmt.Errorf("policy %s: invalid effect '%s'", p.Id, p.Effect)
+		}
+		for _, c := range p.Conditions {
+			if !isOperator(c.Operator) {
+				return nil, fmt.Errorf("policy %s: invalid operator '%s'", p.Id, c.Operator)
+			}
+			if len(c.Attribute) == 0 {
+				return nil, fmt.Errorf("policy %s: attribute is required", p.Id)
+			}
+		}
+		if p.Time != nil {
+			if _, _, err := p.Time.dates(); err != nil {
+				return nil, fmt.Errorf("policy %s: %s", p.Id, err.Error())
+			}
+			if _, _, err := p.Time.times(time.Now()); err != nil {
+				return nil, fmt.Errorf("policy %s: %s", p.Id, err.Error())
+			}
+		}
+	}
+	return &PolicyEngine{Policies: policies}, nil
+}
+
+func NewPolicyEngineFromJSON(data []byte) (*PolicyEngine, error) {
+	var policies []Policy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, err
+	}
+	return NewPolicyEngine(policies)
+}
+
+// NewPolicyEngineFromFile picks the format by extension: .yaml and .yml files are decoded with unmarshal, such as yaml.Unmarshal
+// from gopkg.in/yaml.v3, so this package stays free of a YAML dependency; any other file is read as JSON.
+func NewPolicyEngineFromFile(path string, unmarshal ...func([]byte, interface{}) error) (*PolicyEngine, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" {
+		return NewPolicyEngineFromJSON(data)
+	}
+	if len(unmarshal) == 0 || unmarshal[0] == nil {
+		return nil, fmt.Errorf("cannot load %s: a YAML unmarshal function is required", path)
+	}
+	var policies []Policy
+	if err = unmarshal[0](data, &policies); err != nil {
+		return nil, err
+	}
+	return NewPolicyEngine(policies)
+}
+
+// Evaluate applies deny-overrides: any matching deny policy rejects the request, otherwise at least one allow policy must match.
+func (e *PolicyEngine) Evaluate(req AccessRequest) (bool, string) {
+	allowed := ""
+	for _, p := range e.Policies {
+		if !p.matches(req) {
+			continue
+		}
+		if p.Effect == EffectDeny {
+			return false, p.Id
+		}
+		if len(allowed) == 0 {
+			allowed = p.Id
+			if len(allowed) == 0 {
+				allowed = "*"
+			}
+		}
+	}
+	return len(allowed) > 0, allowed
+}
+
+func (e *PolicyEngine) IsAllowed(req AccessRequest) bool {
+	allowed, _ := e.Evaluate(req)
+	return allowed
+}
+
+func (p Policy) matches(req AccessRequest) bool {
+	if !matchName(p.Actions, req.Action) || !matchName(p.Resources, req.ResourceType) {
+		return false
+	}
+	if p.Time != nil && !p.Time.IsValid() {
+		return false
+	}
+	deny := p.Effect == EffectDeny
+	for _, c := range p.Conditions {
+		if !c.evaluate(req, deny) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchName(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == "*" || n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t PolicyTime) IsValid() bool {
+	dateFrom, dateTo, er1 := t.dates()
+	if er1 != nil || !IsAccessDateValid(dateFrom, dateTo) {
+		return false
+	}
+	timeFrom, timeTo, er2 := t.times(time.Now())
+	if er2 != nil {
+		return false
+	}
+	return IsAccessTimeValid(timeFrom, timeTo)
+}
+
+func (t PolicyTime) dates() (*time.Time, *time.Time, error) {
+	from, er1 := parsePolicyTime("2006-01-02", t.DateFrom, time.Time{})
+	if er1 != nil {
+		return nil, nil, er1
+	}
+	to, er2 := parsePolicyTime("2006-01-02", t.DateTo, time.Time{})
+	return from, to, er2
+}
+
+func (t PolicyTime) times(now time.Time) (*time.Time, *time.Time, error) {
+	from, er1 := parsePolicyTime("15:04", t.TimeFrom, now)
+	if er1 != nil {
+		return nil, nil, er1
+	}
+	to, er2 := parsePolicyTime("15:04", t.TimeTo, now)
+	return from, to, er2
+}
+
+func parsePolicyTime(layout string, s string, day time.Time) (*time.Time, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(layout, s, time.Now().Location())
+	if err != nil {
+		return nil, err
+	}
+	if !day.IsZero() {
+		t = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+	}
+	return &t, nil
+}
+
+func (c Condition) Evaluate(req AccessRequest) bool {
+	return c.evaluate(req, false)
+}
+
+// evaluate treats a missing attribute or ref as a match on deny policies, so a deny rule fails closed when the request
+// lacks the data it checks; guard optional attributes with an "exists" condition to skip the rule instead.
+func (c Condition) evaluate(req AccessRequest, deny bool) bool {
+	v, ok := req.Get(c.Attribute)
+	if c.Operator == OperatorExists {
+		expected, isBool := c.Value.(bool)
+		if !isBool {
+			expected = true
+		}
+		return ok == expected
+	}
+	if !ok {
+		return deny
+	}
+	expected := c.Value
+	if len(c.Ref) > 0 {
+		ref, exist := req.Get(c.Ref)
+		if !exist {
+			return deny
+		}
+		expected = ref
+	}
+	switch c.Operator {
+	case OperatorEqual:
+		return equalValue(v, expected)
+	case OperatorNotEqual:
+		return !equalValue(v, expected)
+	case OperatorIn:
+		return containsValue(expected, v)
+	case OperatorNotIn:
+		return !containsValue(expected, v)
+	case OperatorContains:
+		return containsValue(v, expected)
+	case OperatorGreater:
+		r, ok := compareValue(v, expected)
+		return ok && r > 0
+	case OperatorGreaterEqual:
+		r, ok := compareValue(v, expected)
+		return ok && r >= 0
+	case OperatorLess:
+		r, ok := compareValue(v, expected)
+		return ok && r < 0
+	case OperatorLessEqual:
+		r, ok := compareValue(v, expected)
+		return ok && r <= 0
+	case OperatorCIDR:
+		return inCIDR(v, expected)
+	}
+	return false
+}
+
+// Get resolves attributes such as "subject.branchId", "resource.owner.id" or "environment.ip"; "env" is accepted for "environment".
+func (r AccessRequest) Get(attribute string) (interface{}, bool) {
+	paths := strings.Split(attribute, ".")
+	var v interface{}
+	switch paths[0] {
+	case "subject":
+		v = r.Subject
+	case "resource":
+		v = r.Resource
+	case "environment", "env":
+		v = r.Environment
+	case "action":
+		return r.Action, len(paths) == 1
+	default:
+		return nil, false
+	}
+	for _, path := range paths[1:] {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[path]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+func NewEnvironment(ip string, now time.Time) map[string]interface{} {
+	return map[string]interface{}{"ip": ip, "time": now, "hour": now.Hour(), "weekday": int(now.Weekday())}
+}
+
+func ToSubject(user UserAccount) (map[string]interface{}, error) {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	var subject map[string]interface{}
+	err = json.Unmarshal(data, &subject)
+	return subject, err
+}
+
+func isOperator(op string) bool {
+	switch op {
+	case OperatorEqual, OperatorNotEqual, OperatorIn, OperatorNotIn, OperatorContains, OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual, OperatorExists, OperatorCIDR:
+		return true
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	if _, ok := v.(string); ok {
+		return 0, false
+	}
+	return toFloat(v)
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case string:
+		if p, err := time.Parse(time.RFC3339, t); err == nil {
+			return p, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// equalValue compares strings exactly ("007" is not "7"); only numeric types are compared as numbers.
+func equalValue(a, b interface{}) bool {
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return x == y
+		}
+	}
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			return x == y
+		}
+	}
+	if x, ok := toTime(a); ok {
+		if y, ok := toTime(b); ok {
+			return x.Equal(y)
+		}
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func compareValue(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			if x < y {
+				return -1, true
+			} else if x > y {
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	if x, ok := toTime(a); ok {
+		if y, ok := toTime(b); ok {
+			if x.Before(y) {
+				return -1, true
+			} else if x.After(y) {
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	x, ok1 := a.(string)
+	y, ok2 := b.(string)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+func containsValue(list interface{}, v interface{}) bool {
+	if s, ok := list.(string); ok {
+		if sub, ok := v.(string); ok {
+			return strings.Contains(s, sub)
+		}
+		return false
+	}
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if equalValue(rv.Index(i).Interface(), v) {
+			return true
+		}
+	}
+	return false
+}
+
+func inCIDR(v interface{}, cidr interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+	blocks := make([]string, 0)
+	if c, ok := cidr.(string); ok {
+		blocks = append(blocks, c)
+	} else {
+		rv := reflect.ValueOf(cidr)
+		if rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				blocks = append(blocks, fmt.Sprint(rv.Index(i).Interface()))
+			}
+		}
+	}
+	for _, block := range blocks {
+		_, n, err := net.ParseCIDR(block)
+		if err == nil && n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
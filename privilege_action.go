@@ -0,0 +1,23 @@
+package auth
+
+const (
+	ActionRead    int32 = 1
+	ActionWrite   int32 = 2
+	ActionDelete  int32 = 4
+	ActionApprove int32 = 8
+	ActionAll     int32 = ActionRead | ActionWrite | ActionDelete | ActionApprove
+)
+
+func HasPermission(privileges []Privilege, resource string, action int32) bool {
+	for _, p := range privileges {
+		if p.Resource == resource || len(p.Resource) == 0 && p.Id == resource {
+			if p.Permissions&action == action {
+				return true
+			}
+		}
+		if p.Children != nil && HasPermission(*p.Children, resource, action) {
+			return true
+		}
+	}
+	return false
+}
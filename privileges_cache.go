@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type privilegesItem struct {
+	Privileges []Privilege
+	ExpireAt   time.Time
+}
+
+type PrivilegesCache struct {
+	Privileges func(ctx context.Context, id string) ([]Privilege, error)
+	TTL        time.Duration
+	mu         sync.RWMutex
+	items      map[string]privilegesItem
+}
+
+func NewPrivilegesCache(load func(ctx context.Context, id string) ([]Privilege, error), ttl time.Duration) *PrivilegesCache {
+	return &PrivilegesCache{Privileges: load, TTL: ttl, items: make(map[string]privilegesItem)}
+}
+
+func (c *PrivilegesCache) Load(ctx context.Context, id string) ([]Privilege, error) {
+	now := time.Now()
+	key := privilegesKey(GetTenant(ctx), id)
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if ok && now.Before(item.ExpireAt) {
+		return item.Privileges, nil
+	}
+	privileges, err := c.Privileges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	for k, v := range c.items {
+		if now.After(v.ExpireAt) {
+			delete(c.items, k)
+		}
+	}
+	c.items[key] = privilegesItem{Privileges: privileges, ExpireAt: now.Add(c.TTL)}
+	c.mu.Unlock()
+	return privileges, nil
+}
+
+// Remove drops the cached privileges of the user in every tenant.
+func (c *PrivilegesCache) Remove(id string) {
+	c.mu.Lock()
+	for k := range c.items {
+		if k == id || strings.HasSuffix(k, "\x00"+id) {
+			delete(c.items, k)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// privilegesKey keeps the same user id in two tenants apart.
+func privilegesKey(tenant string, id string) string {
+	if len(tenant) == 0 {
+		return id
+	}
+	return tenant + "\x00" + id
+}
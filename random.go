@@ -1,10 +1,6 @@
 package auth
 
-import (
-	"math"
-	"math/rand"
-	"strconv"
-)
+import "github.com/core-go/authentication/random"
 
 func times(str string, n int) (out string) {
 	for i := 0; i < n; i++ {
@@ -20,8 +16,11 @@ func padLeft(str string, length int, pad string) string {
 }
 
 func Generate(length int) string {
-	max := int(math.Pow(float64(10), float64(length))) - 1
-	return padLeft(strconv.Itoa(rand.Intn(max)), length, "0")
+	code, err := random.Digits(length)
+	if err != nil {
+		panic(err)
+	}
+	return code
 }
 
 func GenerateCode() string {
@@ -0,0 +1,99 @@
+package random
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+const (
+	Numeric      = "0123456789"
+	Alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	// Readable excludes characters that are easily confused, such as 0/O and 1/I/L.
+	Readable = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+	DefaultSessionIdBits = 256
+)
+
+func Bytes(size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, errors.New("size must be greater than 0")
+	}
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// String returns length characters drawn uniformly from alphabet.
+func String(alphabet string, length int) (string, error) {
+	if len(alphabet) == 0 {
+		return "", errors.New("alphabet cannot be empty")
+	}
+	max := big.NewInt(int64(len(alphabet)))
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func Digits(length int) (string, error) {
+	return String(Numeric, length)
+}
+
+func Code(length int) (string, error) {
+	return String(Alphanumeric, length)
+}
+
+// Token returns size random bytes, URL-safe base64 encoded without padding.
+func Token(size int) (string, error) {
+	b, err := Bytes(size)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func Hex(size int) (string, error) {
+	b, err := Bytes(size)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func SessionId(ctx context.Context) (string, error) {
+	return Token(DefaultSessionIdBits / 8)
+}
+
+// NewSessionId returns a session id generator with the given entropy in bits, at least 128.
+func NewSessionId(bits int) func(ctx context.Context) (string, error) {
+	if bits < 128 {
+		bits = 128
+	}
+	size := (bits + 7) / 8
+	return func(ctx context.Context) (string, error) {
+		return Token(size)
+	}
+}
+
+// NewPasscode returns a numeric passcode generator, matching the func() string shape of Authenticator.GenerateCode.
+// It panics if the system random source fails.
+func NewPasscode(length int) func() string {
+	return func() string {
+		code, err := Digits(length)
+		if err != nil {
+			panic(err)
+		}
+		return code
+	}
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type RateLimitStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Put(ctx context.Context, key string, value string, timeToLive time.Duration) error
+}
+
+type CacheRateLimitStore struct {
+	Cache CachePort
+}
+
+func NewCacheRateLimitStore(cache CachePort) *CacheRateLimitStore {
+	return &CacheRateLimitStore{Cache: cache}
+}
+
+func (s *CacheRateLimitStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.Cache.Get(ctx, key)
+	if IsCacheMiss(err) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (s *CacheRateLimitStore) Put(ctx context.Context, key string, value string, timeToLive time.Duration) error {
+	return s.Cache.Put(ctx, key, value, timeToLive)
+}
+
+// keyLocks serializes the read-modify-write of one key without blocking the other keys.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*keyLock)
+	}
+	k, ok := l.locks[key]
+	if !ok {
+		k = &keyLock{}
+		l.locks[key] = k
+	}
+	k.refs++
+	l.mu.Unlock()
+	k.Lock()
+	return func() {
+		k.Unlock()
+		l.mu.Lock()
+		k.refs--
+		if k.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,10 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (bool, time.Duration, error)
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+type RBAC struct {
+	Roles      map[string]Role
+	Modules    []Module
+	Repository RoleRepository
+	expanded   map[string][]string
+}
+
+func NewRBAC(roles []Role, modules []Module, options ...RoleRepository) (*RBAC, error) {
+	m := make(map[string]Role)
+	for _, role := range roles {
+		m[role.Id] = role
+	}
+	expanded := make(map[string][]string)
+	for _, role := range roles {
+		ids, err := expandRole(m, role.Id, make(map[string]bool), nil)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(ids)
+		expanded[role.Id] = ids
+	}
+	var repository RoleRepository
+	if len(options) > 0 {
+		repository = options[0]
+	}
+	return &RBAC{Roles: m, Modules: modules, Repository: repository, expanded: expanded}, nil
+}
+
+func NewRBACByRepository(ctx context.Context, repository RoleRepository, modules []Module) (*RBAC, error) {
+	roles, err := repository.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewRBAC(roles, modules, repository)
+}
+
+func expandRole(roles map[string]Role, id string, visiting map[string]bool, result []string) ([]string, error) {
+	if visiting[id] {
+		return nil, errors.New("cyclic role inheritance at " + id)
+	}
+	role, ok := roles[id]
+	if !ok {
+		return nil, errors.New("unknown role " + id)
+	}
+	for _, r := range result {
+		if r == id {
+			return result, nil
+		}
+	}
+	result = append(result, id)
+	visiting[id] = true
+	var err error
+	for _, parent := range role.Inherits {
+		if result, err = expandRole(roles, parent, visiting, result); err != nil {
+			return nil, err
+		}
+	}
+	delete(visiting, id)
+	return result, nil
+}
+
+func (r *RBAC) Expand(roles []string) []string {
+	set := make(map[string]bool)
+	result := make([]string, 0)
+	for _, role := range roles {
+		ids, ok := r.expanded[role]
+		if !ok {
+			ids = []string{role}
+		}
+		for _, id := range ids {
+			if !set[id] {
+				set[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	return result
+}
+
+func (r *RBAC) HasRole(userRoles []string, roles ...string) bool {
+	return ContainsRole(r.Expand(userRoles), roles...)
+}
+
+func (r *RBAC) ToModules(roles []string) []Module {
+	permissions := make(map[string]int32)
+	for _, id := range r.Expand(roles) {
+		if role, ok := r.Roles[id]; ok {
+			for _, m := range role.Modules {
+				permissions[m.ModuleId] = permissions[m.ModuleId] | m.Permissions
+			}
+		}
+	}
+	granted := make(map[string]bool)
+	for _, m := range r.Modules {
+		if _, ok := permissions[m.Id]; ok {
+			granted[m.Id] = true
+			if m.Parent != nil && len(*m.Parent) > 0 {
+				granted[*m.Parent] = true
+			}
+		}
+	}
+	empty := ""
+	modules := make([]Module, 0)
+	for _, m := range r.Modules {
+		if granted[m.Id] {
+			m.Permissions = permissions[m.Id]
+			if m.Parent == nil {
+				m.Parent = &empty
+			}
+			modules = append(modules, m)
+		}
+	}
+	return modules
+}
+
+func (r *RBAC) ToPrivileges(roles []string) []Privilege {
+	return ToPrivileges(r.ToModules(roles))
+}
+
+func (r *RBAC) Load(ctx context.Context, userId string) ([]Privilege, error) {
+	roles, err := r.userRoles(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return r.ToPrivileges(roles), nil
+}
+
+func (r *RBAC) UserHasRole(ctx context.Context, userId string, roles ...string) (bool, error) {
+	userRoles, err := r.userRoles(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	return r.HasRole(userRoles, roles...), nil
+}
+
+func (r *RBAC) userRoles(ctx context.Context, userId string) ([]string, error) {
+	if r.Repository == nil {
+		return nil, errors.New("role repository is not configured")
+	}
+	return r.Repository.GetUserRoles(ctx, userId)
+}
+
+func ContainsRole(userRoles []string, roles ...string) bool {
+	for _, role := range roles {
+		for _, id := range userRoles {
+			if id == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ToRoles(v interface{}) []string {
+	switch roles := v.(type) {
+	case []string:
+		return roles
+	case []interface{}:
+		result := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if s, ok := role.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		if len(roles) == 0 {
+			return nil
+		}
+		return strings.Split(roles, ",")
+	}
+	return nil
+}
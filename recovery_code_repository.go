@@ -0,0 +1,9 @@
+package auth
+
+import "context"
+
+type RecoveryCodeRepository interface {
+	Load(ctx context.Context, id string) ([]string, error)
+	Save(ctx context.Context, id string, codes []string) (int64, error)
+	Delete(ctx context.Context, id string, code string) (int64, error)
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/core-go/authentication/random"
+)
+
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+type RecoveryCodeService struct {
+	Repository RecoveryCodeRepository
+	Comparator ValueComparator
+	Count      int
+	Length     int
+}
+
+func NewRecoveryCodeService(repository RecoveryCodeRepository, comparator ValueComparator, options ...int) *RecoveryCodeService {
+	if repository == nil || comparator == nil {
+		panic(errors.New("recovery code repository and comparator cannot be nil"))
+	}
+	count := 10
+	length := 10
+	if len(options) > 0 && options[0] > 0 {
+		count = options[0]
+	}
+	if len(options) > 1 && options[1] > 0 {
+		length = options[1]
+	}
+	return &RecoveryCodeService{Repository: repository, Comparator: comparator, Count: count, Length: length}
+}
+
+func (s *RecoveryCodeService) Generate(ctx context.Context, id string) ([]string, error) {
+	codes := make([]string, 0, s.Count)
+	hashes := make([]string, 0, s.Count)
+	for i := 0; i < s.Count; i++ {
+		code, err := generateRecoveryCode(s.Length)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := s.Comparator.Hash(code)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, formatRecoveryCode(code))
+		hashes = append(hashes, hash)
+	}
+	if _, err := s.Repository.Save(ctx, id, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *RecoveryCodeService) Remaining(ctx context.Context, id string) (int, error) {
+	hashes, err := s.Repository.Load(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return len(hashes), nil
+}
+
+// IsRecoveryCode reports whether code has the shape of a recovery code, so that other passcodes never reach the hash compares.
+func (s *RecoveryCodeService) IsRecoveryCode(code string) bool {
+	code = normalizeRecoveryCode(code)
+	if len(code) != s.Length {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(recoveryCodeAlphabet, code[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *RecoveryCodeService) Verify(ctx context.Context, id string, code string) (bool, error) {
+	if !s.IsRecoveryCode(code) {
+		return false, nil
+	}
+	code = normalizeRecoveryCode(code)
+	hashes, err := s.Repository.Load(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range hashes {
+		valid, er1 := s.Comparator.Compare(code, hash)
+		if er1 != nil {
+			return false, er1
+		}
+		if valid {
+			count, er2 := s.Repository.Delete(ctx, id, hash)
+			if er2 != nil {
+				return false, er2
+			}
+			return count > 0, nil
+		}
+	}
+	return false, nil
+}
+
+func generateRecoveryCode(length int) (string, error) {
+	return random.String(recoveryCodeAlphabet, length)
+}
+
+func formatRecoveryCode(code string) string {
+	if len(code) < 8 {
+		return code
+	}
+	return code[:len(code)/2] + "-" + code[len(code)/2:]
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "time"
+
+type RefreshToken struct {
+	Id        string                 `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	FamilyId  string                 `yaml:"family_id" mapstructure:"family_id" json:"familyId,omitempty" gorm:"column:familyid" bson:"familyId,omitempty" dynamodbav:"familyId,omitempty" firestore:"familyId,omitempty"`
+	UserId    string                 `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:userid" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Payload   map[string]interface{} `yaml:"payload" mapstructure:"payload" json:"payload,omitempty" gorm:"column:payload" bson:"payload,omitempty" dynamodbav:"payload,omitempty" firestore:"payload,omitempty"`
+	Used      bool                   `yaml:"used" mapstructure:"used" json:"used,omitempty" gorm:"column:used" bson:"used,omitempty" dynamodbav:"used,omitempty" firestore:"used,omitempty"`
+	CreatedAt time.Time              `yaml:"created_at" mapstructure:"created_at" json:"createdAt,omitempty" gorm:"column:createdat" bson:"createdAt,omitempty" dynamodbav:"createdAt,omitempty" firestore:"createdAt,omitempty"`
+	ExpiresAt time.Time              `yaml:"expires_at" mapstructure:"expires_at" json:"expiresAt,omitempty" gorm:"column:expiresat" bson:"expiresAt,omitempty" dynamodbav:"expiresAt,omitempty" firestore:"expiresAt,omitempty"`
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/core-go/authentication/random"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenRevoked = errors.New("refresh token has been revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token has been reused")
+)
+
+type RefreshTokenService struct {
+	Store           RefreshTokenStore
+	Expires         int64
+	RevokeAllTokens func(ctx context.Context, id string, reason string) error
+}
+
+func NewRefreshTokenService(store RefreshTokenStore, expires int64, options ...func(context.Context, string, string) error) *RefreshTokenService {
+	if store == nil || expires <= 0 {
+		panic(errors.New("refresh token store cannot be nil, and expires must be greater than 0"))
+	}
+	var revokeAllTokens func(context.Context, string, string) error
+	if len(options) > 0 {
+		revokeAllTokens = options[0]
+	}
+	return &RefreshTokenService{Store: store, Expires: expires, RevokeAllTokens: revokeAllTokens}
+}
+
+func (s *RefreshTokenService) Issue(ctx context.Context, userId string, payload map[string]interface{}) (string, error) {
+	familyId, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	return s.issue(ctx, familyId, userId, payload, now, now.Add(s.expires()))
+}
+
+func (s *RefreshTokenService) Rotate(ctx context.Context, refreshToken string) (string, map[string]interface{}, error) {
+	token, err := s.Store.Get(ctx, hashToken(refreshToken))
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	if token == nil || now.After(token.ExpiresAt) {
+		return "", nil, ErrRefreshTokenInvalid
+	}
+	revoked, err := s.Store.IsRevoked(ctx, token.FamilyId)
+	if err != nil {
+		return "", nil, err
+	}
+	if revoked {
+		return "", nil, ErrRefreshTokenRevoked
+	}
+	// Use is the store's compare-and-set, so of two concurrent rotations only one wins and the other is treated as reuse
+	used := token.Used
+	if !used {
+		ok, er1 := s.Store.Use(ctx, token.Id, token.ExpiresAt.Sub(now))
+		if er1 != nil {
+			return "", nil, er1
+		}
+		used = !ok
+	}
+	if used {
+		if err = s.revoke(ctx, *token, "refresh token reused"); err != nil {
+			return "", nil, err
+		}
+		return "", nil, ErrRefreshTokenReused
+	}
+	newToken, err := s.issue(ctx, token.FamilyId, token.UserId, token.Payload, now, token.ExpiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return newToken, token.Payload, nil
+}
+
+func (s *RefreshTokenService) Revoke(ctx context.Context, refreshToken string) error {
+	token, err := s.Store.Get(ctx, hashToken(refreshToken))
+	if err != nil || token == nil {
+		return err
+	}
+	return s.Store.RevokeFamily(ctx, token.FamilyId, s.expires())
+}
+
+func (s *RefreshTokenService) revoke(ctx context.Context, token RefreshToken, reason string) error {
+	if err := s.Store.RevokeFamily(ctx, token.FamilyId, s.expires()); err != nil {
+		return err
+	}
+	if s.RevokeAllTokens != nil && len(token.UserId) > 0 {
+		return s.RevokeAllTokens(ctx, token.UserId, reason)
+	}
+	return nil
+}
+
+func (s *RefreshTokenService) issue(ctx context.Context, familyId string, userId string, payload map[string]interface{}, now time.Time, expiresAt time.Time) (string, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	token := RefreshToken{Id: hashToken(value), FamilyId: familyId, UserId: userId, Payload: payload, CreatedAt: now, ExpiresAt: expiresAt}
+	if err = s.Store.Save(ctx, token, expiresAt.Sub(now)); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func (s *RefreshTokenService) expires() time.Duration {
+	return time.Duration(s.Expires) * time.Millisecond
+}
+
+func randomToken(size int) (string, error) {
+	return random.Token(size)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
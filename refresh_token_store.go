@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type RefreshTokenStore interface {
+	Get(ctx context.Context, id string) (*RefreshToken, error)
+	Save(ctx context.Context, token RefreshToken, timeToLive time.Duration) error
+	// Use atomically marks the token as used; it returns false when the token was already used or does not exist.
+	Use(ctx context.Context, id string, timeToLive time.Duration) (bool, error)
+	IsRevoked(ctx context.Context, familyId string) (bool, error)
+	RevokeFamily(ctx context.Context, familyId string, timeToLive time.Duration) error
+}
+
+type CacheRefreshTokenStore struct {
+	Cache  CachePort
+	Prefix string
+	locks  keyLocks
+}
+
+func NewCacheRefreshTokenStore(cache CachePort, options ...string) *CacheRefreshTokenStore {
+	prefix := "refresh:"
+	if len(options) > 0 {
+		prefix = options[0]
+	}
+	return &CacheRefreshTokenStore{Cache: cache, Prefix: prefix}
+}
+
+func (s *CacheRefreshTokenStore) get(ctx context.Context, key string) (string, error) {
+	value, err := s.Cache.Get(ctx, key)
+	if IsCacheMiss(err) {
+		return "", nil
+	}
+	return value, err
+}
+func (s *CacheRefreshTokenStore) Get(ctx context.Context, id string) (*RefreshToken, error) {
+	value, err := s.get(ctx, s.Prefix+"token:"+id)
+	if err != nil || len(value) == 0 {
+		return nil, err
+	}
+	var token RefreshToken
+	if err = json.Unmarshal([]byte(value), &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+func (s *CacheRefreshTokenStore) Save(ctx context.Context, token RefreshToken, timeToLive time.Duration) error {
+	value, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return s.Cache.Put(ctx, s.Prefix+"token:"+token.Id, string(value), timeToLive)
+}
+func (s *CacheRefreshTokenStore) Use(ctx context.Context, id string, timeToLive time.Duration) (bool, error) {
+	key := s.Prefix + "used:" + id
+	if adder, ok := s.Cache.(CacheAdder); ok {
+		return adder.Add(ctx, key, "used", timeToLive)
+	}
+	// without an atomic add, rotation is only serialized within this process
+	unlock := s.locks.lock(key)
+	defer unlock()
+	value, err := s.get(ctx, key)
+	if err != nil || len(value) > 0 {
+		return false, err
+	}
+	return true, s.Cache.Put(ctx, key, "used", timeToLive)
+}
+func (s *CacheRefreshTokenStore) IsRevoked(ctx context.Context, familyId string) (bool, error) {
+	value, err := s.get(ctx, s.Prefix+"family:"+familyId)
+	return len(value) > 0, err
+}
+func (s *CacheRefreshTokenStore) RevokeFamily(ctx context.Context, familyId string, timeToLive time.Duration) error {
+	return s.Cache.Put(ctx, s.Prefix+"family:"+familyId, "revoked", timeToLive)
+}
+
+type refreshTokenItem struct {
+	Token    RefreshToken
+	ExpireAt time.Time
+}
+
+type MemoryRefreshTokenStore struct {
+	mu       sync.Mutex
+	tokens   map[string]refreshTokenItem
+	families map[string]time.Time
+	saves    int
+}
+
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{tokens: make(map[string]refreshTokenItem), families: make(map[string]time.Time)}
+}
+
+func (s *MemoryRefreshTokenStore) Get(ctx context.Context, id string) (*RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.get(id, time.Now())
+	if !ok {
+		return nil, nil
+	}
+	token := item.Token
+	return &token, nil
+}
+func (s *MemoryRefreshTokenStore) Save(ctx context.Context, token RefreshToken, timeToLive time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	expireAt := token.ExpiresAt
+	if timeToLive > 0 && (expireAt.IsZero() || now.Add(timeToLive).Before(expireAt)) {
+		expireAt = now.Add(timeToLive)
+	}
+	s.tokens[token.Id] = refreshTokenItem{Token: token, ExpireAt: expireAt}
+	s.saves++
+	if s.saves >= 1000 {
+		s.saves = 0
+		s.purge(now)
+	}
+	return nil
+}
+func (s *MemoryRefreshTokenStore) Use(ctx context.Context, id string, timeToLive time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.get(id, time.Now())
+	if !ok || item.Token.Used {
+		return false, nil
+	}
+	item.Token.Used = true
+	s.tokens[id] = item
+	return true, nil
+}
+func (s *MemoryRefreshTokenStore) IsRevoked(ctx context.Context, familyId string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.families[familyId]
+	if ok && time.Now().After(expiresAt) {
+		delete(s.families, familyId)
+		return false, nil
+	}
+	return ok, nil
+}
+func (s *MemoryRefreshTokenStore) RevokeFamily(ctx context.Context, familyId string, timeToLive time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.families[familyId] = time.Now().Add(timeToLive)
+	for id, item := range s.tokens {
+		if item.Token.FamilyId == familyId {
+			delete(s.tokens, id)
+		}
+	}
+	return nil
+}
+func (s *MemoryRefreshTokenStore) get(id string, now time.Time) (refreshTokenItem, bool) {
+	item, ok := s.tokens[id]
+	if !ok {
+		return item, false
+	}
+	if now.After(item.ExpireAt) {
+		delete(s.tokens, id)
+		return item, false
+	}
+	return item, true
+}
+func (s *MemoryRefreshTokenStore) purge(now time.Time) {
+	for id, item := range s.tokens {
+		if now.After(item.ExpireAt) {
+			delete(s.tokens, id)
+		}
+	}
+	for familyId, expiresAt := range s.families {
+		if now.After(expiresAt) {
+			delete(s.families, familyId)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	auth "github.com/core-go/authentication"
+)
+
+type PasswordRepository struct {
+	db                      *sql.DB
+	BuildParam              func(i int) string
+	userTableName           string
+	passwordTableName       string
+	historyTableName        string
+	IdName                  string
+	UserName                string
+	UserId                  string
+	PasswordName            string
+	PasswordChangedTimeName string
+	FailCountName           string
+	LockedUntilTimeName     string
+	ContactName             string
+	EmailName               string
+	DisplayNameName         string
+}
+
+func NewPasswordRepositoryByConfig(db *sql.DB, buildParam func(i int) string, userTableName, passwordTableName, historyTableName string, c auth.SchemaConfig) *PasswordRepository {
+	return NewPasswordRepository(db, buildParam, userTableName, passwordTableName, historyTableName, c.Id, c.Username, c.UserId, c.Password, c.PasswordChangedTime, c.FailCount, c.LockedUntilTime, c.Contact, c.Email, c.DisplayName)
+}
+
+func NewPasswordRepository(db *sql.DB, buildParam func(i int) string, userTableName, passwordTableName, historyTableName, idName, userName, userID, passwordName, passwordChangedTimeName, failCountName, lockedUntilTimeName, contactName, emailName, displayNameName string) *PasswordRepository {
+	var b = buildParam
+	if b == nil {
+		b = getBuild(db)
+	}
+	if len(passwordTableName) == 0 {
+		passwordTableName = userTableName
+	}
+	if len(idName) == 0 {
+		idName = "id"
+	}
+	if len(userName) == 0 {
+		userName = "username"
+	}
+	if len(userID) == 0 {
+		userID = "userid"
+	}
+	if len(passwordName) == 0 {
+		passwordName = "password"
+	}
+	return &PasswordRepository{
+		db:                      db,
+		BuildParam:              b,
+		userTableName:           strings.ToLower(userTableName),
+		passwordTableName:       strings.ToLower(passwordTableName),
+		historyTableName:        strings.ToLower(historyTableName),
+		IdName:                  strings.ToLower(idName),
+		UserName:                strings.ToLower(userName),
+		UserId:                  strings.ToLower(userID),
+		PasswordName:            strings.ToLower(passwordName),
+		PasswordChangedTimeName: strings.ToLower(passwordChangedTimeName),
+		FailCountName:           strings.ToLower(failCountName),
+		LockedUntilTimeName:     strings.ToLower(lockedUntilTimeName),
+		ContactName:             strings.ToLower(contactName),
+		EmailName:               strings.ToLower(emailName),
+		DisplayNameName:         strings.ToLower(displayNameName),
+	}
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*auth.UserInfo, error) {
+	var id, username string
+	var password, email, phone, displayName sql.NullString
+	columns := []string{r.IdName, r.UserName}
+	values := []interface{}{&id, &username}
+	where := r.UserName + " = " + r.BuildParam(1)
+	params := []interface{}{contact}
+	if len(r.EmailName) > 0 {
+		columns = append(columns, r.EmailName)
+		values = append(values, &email)
+		where += " OR " + r.EmailName + " = " + r.BuildParam(2)
+		params = append(params, contact)
+	}
+	if len(r.ContactName) > 0 {
+		columns = append(columns, r.ContactName)
+		values = append(values, &phone)
+	}
+	if len(r.DisplayNameName) > 0 {
+		columns = append(columns, r.DisplayNameName)
+		values = append(values, &displayName)
+	}
+	if r.userTableName == r.passwordTableName {
+		columns = append(columns, r.PasswordName)
+		values = append(values, &password)
+	}
+	query := `SELECT ` + strings.Join(columns, ", ") + ` FROM ` + r.userTableName + ` WHERE ` + where + ` LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query, params...).Scan(values...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.userTableName != r.passwordTableName {
+		query = `SELECT ` + r.PasswordName + ` FROM ` + r.passwordTableName + ` WHERE ` + r.IdName + ` = ` + r.BuildParam(1)
+		err = r.db.QueryRowContext(ctx, query, id).Scan(&password)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+	user := auth.UserInfo{Id: id, Username: username, Password: password.String}
+	if email.Valid {
+		user.Email = &email.String
+	}
+	if phone.Valid {
+		user.Contact = &phone.String
+	}
+	if displayName.Valid {
+		user.DisplayName = &displayName.String
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if len(r.historyTableName) == 0 || max <= 0 {
+		return passwords, nil
+	}
+	query := `SELECT ` + r.PasswordName + ` FROM ` + r.historyTableName + ` WHERE ` + r.UserId + ` = ` + r.BuildParam(1) + ` ORDER BY ` + r.historyTimeName() + ` DESC`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() && len(passwords) < max {
+		var password string
+		if err = rows.Scan(&password); err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, password)
+	}
+	return passwords, rows.Err()
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	i := 1
+	columns := []string{r.PasswordName + " = " + r.BuildParam(i)}
+	params := []interface{}{newPassword}
+	i = i + 1
+	if len(r.PasswordChangedTimeName) > 0 {
+		columns = append(columns, r.PasswordChangedTimeName+" = "+r.BuildParam(i))
+		params = append(params, now)
+		i = i + 1
+	}
+	if len(r.FailCountName) > 0 {
+		columns = append(columns, r.FailCountName+" = 0")
+	}
+	if len(r.LockedUntilTimeName) > 0 {
+		columns = append(columns, r.LockedUntilTimeName+" = NULL")
+	}
+	params = append(params, id)
+	query := `UPDATE ` + r.passwordTableName + ` SET ` + strings.Join(columns, ", ") + ` WHERE ` + r.IdName + ` = ` + r.BuildParam(i)
+	result, err := r.db.ExecContext(ctx, query, params...)
+	if err != nil {
+		return 0, err
+	}
+	k, err := result.RowsAffected()
+	if err != nil || k <= 0 || len(r.historyTableName) == 0 {
+		return k, err
+	}
+	insert := `INSERT INTO ` + r.historyTableName + ` (` + r.UserId + `, ` + r.PasswordName + `, ` + r.historyTimeName() + `) VALUES (` + r.BuildParam(1) + `, ` + r.BuildParam(2) + `, ` + r.BuildParam(3) + `)`
+	if _, err = r.db.ExecContext(ctx, insert, id, newPassword, now); err != nil {
+		return -1, err
+	}
+	return k, nil
+}
+
+func (r *PasswordRepository) historyTimeName() string {
+	if len(r.PasswordChangedTimeName) > 0 {
+		return r.PasswordChangedTimeName
+	}
+	return "passwordchangedtime"
+}
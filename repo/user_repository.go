@@ -40,10 +40,13 @@ type AuthenticationRepository struct {
 	AccessTimeFromName      string
 	AccessTimeToName        string
 	TwoFactorsName          string
+	TenantName              string
 }
 
 func NewAuthenticationRepositoryByConfig(db *sql.DB, buildParam func(i int) string, userTableName, passwordTableName string, activatedStatus string, status auth.UserStatusConfig, c auth.SchemaConfig, options ...func(context.Context, string) (bool, error)) *AuthenticationRepository {
-	return NewAuthenticationRepository(db, buildParam, userTableName, passwordTableName, activatedStatus, status, c.Id, c.Username, c.UserId, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r := NewAuthenticationRepository(db, buildParam, userTableName, passwordTableName, activatedStatus, status, c.Id, c.Username, c.UserId, c.SuccessTime, c.FailTime, c.FailCount, c.LockedUntilTime, c.Status, c.PasswordChangedTime, c.Password, c.Contact, c.Email, c.Phone, c.DisplayName, c.MaxPasswordAge, c.UserType, c.AccessDateFrom, c.AccessDateTo, c.AccessTimeFrom, c.AccessTimeTo, c.TwoFactors, options...)
+	r.TenantName = strings.ToLower(c.Tenant)
+	return r
 }
 
 func NewAuthenticationRepository(db *sql.DB, buildParam func(i int) string, userTableName, passwordTableName string, activatedStatus string, status auth.UserStatusConfig, idName, userName, userID, successTimeName, failTimeName, failCountName, lockedUntilTimeName, statusName, passwordChangedTimeName, passwordName, contactName, emailName, phoneName, displayNameName, maxPasswordAgeName, userTypeName, accessDateFromName, accessDateToName, accessTimeFromName, accessTimeToName, twoFactorsName string, options ...func(context.Context, string) (bool, error)) *AuthenticationRepository {
@@ -162,12 +165,22 @@ func (r *AuthenticationRepository) GetUserInfo(ctx context.Context, userid strin
 		strSQL += r.PasswordChangedTimeName + ", "
 	}
 	strSQL = strings.TrimRight(strSQL, ", ")
+	params := []interface{}{userid}
+	tenantSQL := ""
+	if len(r.TenantName) > 0 {
+		tenant := auth.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		tenantSQL = ` AND ` + r.userTableName + `.` + r.TenantName + ` = ` + r.BuildParam(2)
+		params = append(params, tenant)
+	}
 	if r.userTableName == r.passwordTableName {
 		query := `SELECT ` + strSQL +
 			` FROM ` + r.userTableName +
-			` WHERE userid = ` + r.BuildParam(1) +
+			` WHERE userid = ` + r.BuildParam(1) + tenantSQL +
 			` LIMIT 1`
-		rows, err := r.db.QueryContext(ctx, query, userid)
+		rows, err := r.db.QueryContext(ctx, query, params...)
 		if err != nil {
 			return nil, err
 		}
@@ -180,8 +193,8 @@ func (r *AuthenticationRepository) GetUserInfo(ctx context.Context, userid strin
 			` FROM ` + r.userTableName +
 			` INNER JOIN ` + r.passwordTableName +
 			` ON ` + r.passwordTableName + `.` + r.UserId + " = " + r.userTableName + "." + r.UserId +
-			` WHERE ` + r.userTableName + `.` + `userid = ` + r.BuildParam(1)
-		rows, err := r.db.QueryContext(ctx, query, userid)
+			` WHERE ` + r.userTableName + `.` + `userid = ` + r.BuildParam(1) + tenantSQL
+		rows, err := r.db.QueryContext(ctx, query, params...)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,13 @@
+package auth
+
+type Role struct {
+	Id       string       `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Name     string       `yaml:"name" mapstructure:"name" json:"name,omitempty" gorm:"column:name" bson:"name,omitempty" dynamodbav:"name,omitempty" firestore:"name,omitempty"`
+	Inherits []string     `yaml:"inherits" mapstructure:"inherits" json:"inherits,omitempty" gorm:"column:inherits" bson:"inherits,omitempty" dynamodbav:"inherits,omitempty" firestore:"inherits,omitempty"`
+	Modules  []RoleModule `yaml:"modules" mapstructure:"modules" json:"modules,omitempty" gorm:"column:modules" bson:"modules,omitempty" dynamodbav:"modules,omitempty" firestore:"modules,omitempty"`
+}
+
+type RoleModule struct {
+	ModuleId    string `yaml:"module_id" mapstructure:"module_id" json:"moduleId,omitempty" gorm:"column:moduleid" bson:"moduleId,omitempty" dynamodbav:"moduleId,omitempty" firestore:"moduleId,omitempty"`
+	Permissions int32  `yaml:"permissions" mapstructure:"permissions" json:"permissions,omitempty" gorm:"column:permissions" bson:"permissions,omitempty" dynamodbav:"permissions,omitempty" firestore:"permissions,omitempty"`
+}
@@ -0,0 +1,10 @@
+package auth
+
+import "context"
+
+type RoleRepository interface {
+	All(ctx context.Context) ([]Role, error)
+	GetUserRoles(ctx context.Context, userId string) ([]string, error)
+	AssignRole(ctx context.Context, userId string, roleId string) (int64, error)
+	RevokeRole(ctx context.Context, userId string, roleId string) (int64, error)
+}
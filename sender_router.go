@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrNoSender = errors.New("no sender available for the user")
+
+type CodeDelivery interface {
+	Deliver(ctx context.Context, channel string, user UserInfo, code string, expireAt time.Time) error
+}
+
+type SenderRouter struct {
+	Senders map[string]Sender
+	Order   []string
+}
+
+func NewSenderRouter(senders map[string]Sender, order ...string) *SenderRouter {
+	if len(senders) == 0 {
+		panic(errors.New("senders cannot be empty"))
+	}
+	if len(order) == 0 {
+		order = []string{SenderEmail, SenderSMS, SenderVoice}
+	}
+	return &SenderRouter{Senders: senders, Order: order}
+}
+
+// Deliver sends the code through the requested channel, or through the first channel in Order the user has an address for.
+func (r *SenderRouter) Deliver(ctx context.Context, channel string, user UserInfo, code string, expireAt time.Time) error {
+	channels := r.Order
+	if len(channel) > 0 {
+		channels = append([]string{strings.ToLower(channel)}, r.Order...)
+	}
+	for _, c := range channels {
+		sender, ok := r.Senders[c]
+		if !ok {
+			continue
+		}
+		to := address(c, user)
+		if len(to) == 0 {
+			continue
+		}
+		msg := CodeMessage{Channel: c, To: to, Code: code, ExpireAt: expireAt, Expires: int64(time.Until(expireAt).Round(time.Minute).Minutes()), Username: user.Username}
+		if user.DisplayName != nil {
+			msg.DisplayName = *user.DisplayName
+		}
+		if user.Language != nil {
+			msg.Language = *user.Language
+		}
+		return sender.Send(ctx, msg)
+	}
+	return ErrNoSender
+}
+
+// SendCode matches Authenticator.SendCode and PasswordService.SendCode; params is either *UserInfo or the contact as *string.
+func (r *SenderRouter) SendCode(ctx context.Context, to string, code string, expireAt time.Time, params interface{}) error {
+	switch p := params.(type) {
+	case *UserInfo:
+		if p != nil {
+			return r.Deliver(ctx, "", *p, code, expireAt)
+		}
+	case *string:
+		if p != nil && len(*p) > 0 {
+			to = *p
+		}
+	}
+	user := UserInfo{Username: to}
+	if strings.Contains(to, "@") {
+		user.Email = &to
+	} else {
+		user.Phone = &to
+	}
+	return r.Deliver(ctx, "", user, code, expireAt)
+}
+
+func address(channel string, user UserInfo) string {
+	var v *string
+	switch channel {
+	case SenderEmail:
+		v = user.Email
+		if v == nil && user.Contact != nil && strings.Contains(*user.Contact, "@") {
+			v = user.Contact
+		}
+	case SenderSMS, SenderVoice:
+		v = user.Phone
+		if v == nil && user.Contact != nil && !strings.Contains(*user.Contact, "@") {
+			v = user.Contact
+		}
+	}
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(*v)
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "time"
+
+type SessionInfo struct {
+	Id        string    `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	UserId    string    `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:userid" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Ip        string    `yaml:"ip" mapstructure:"ip" json:"ip,omitempty" gorm:"column:ip" bson:"ip,omitempty" dynamodbav:"ip,omitempty" firestore:"ip,omitempty"`
+	UserAgent string    `yaml:"user_agent" mapstructure:"user_agent" json:"userAgent,omitempty" gorm:"column:useragent" bson:"userAgent,omitempty" dynamodbav:"userAgent,omitempty" firestore:"userAgent,omitempty"`
+	CreatedAt time.Time `yaml:"created_at" mapstructure:"created_at" json:"createdAt,omitempty" gorm:"column:createdat" bson:"createdAt,omitempty" dynamodbav:"createdAt,omitempty" firestore:"createdAt,omitempty"`
+	LastSeen  time.Time `yaml:"last_seen" mapstructure:"last_seen" json:"lastSeen,omitempty" gorm:"column:lastseen" bson:"lastSeen,omitempty" dynamodbav:"lastSeen,omitempty" firestore:"lastSeen,omitempty"`
+	Current   bool      `yaml:"current" mapstructure:"current" json:"current,omitempty" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-"`
+}
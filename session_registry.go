@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+type SessionStore interface {
+	Put(ctx context.Context, key string, obj interface{}, timeToLive time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	Remove(ctx context.Context, key string) (bool, error)
+}
+
+// SessionRegistry keeps the list of active sessions of each user under Prefix+userId; the session data itself stays under the session id.
+type SessionRegistry struct {
+	Store         SessionStore
+	Expired       time.Duration
+	MaxSessions   int
+	Prefix        string
+	TouchInterval time.Duration
+	locks         keyLocks
+}
+
+// sessionListLockTime bounds how long a crashed process can hold the lock of a session list.
+const sessionListLockTime = 5 * time.Second
+
+// SessionHandle is the opaque id of a session shown to its user; the session id itself is the cookie value.
+func SessionHandle(sessionId string) string {
+	h := sha256.Sum256([]byte(sessionId))
+	return hex.EncodeToString(h[:16])
+}
+
+func NewSessionRegistry(store SessionStore, expired time.Duration, maxSessions int, options ...string) *SessionRegistry {
+	var prefix string
+	if len(options) > 0 {
+		prefix = options[0]
+	} else {
+		prefix = "sessions:"
+	}
+	return &SessionRegistry{Store: store, Expired: expired, MaxSessions: maxSessions, Prefix: prefix, TouchInterval: time.Minute}
+}
+
+// Add registers a new session and returns the ids of the oldest sessions evicted to respect MaxSessions.
+func (s *SessionRegistry) Add(ctx context.Context, session SessionInfo) ([]string, error) {
+	unlock, err := s.lock(ctx, session.UserId)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	if session.LastSeen.IsZero() {
+		session.LastSeen = session.CreatedAt
+	}
+	sessions, er1 := s.List(ctx, session.UserId)
+	if er1 != nil {
+		return nil, er1
+	}
+	sessions = append(sessions, session)
+	evicted := make([]string, 0)
+	if s.MaxSessions > 0 && len(sessions) > s.MaxSessions {
+		sort.SliceStable(sessions, func(i, j int) bool {
+			return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+		})
+		n := len(sessions) - s.MaxSessions
+		for _, old := range sessions[:n] {
+			if _, er2 := s.Store.Remove(ctx, old.Id); er2 != nil {
+				return nil, er2
+			}
+			evicted = append(evicted, old.Id)
+		}
+		sessions = sessions[n:]
+	}
+	return evicted, s.save(ctx, session.UserId, sessions)
+}
+
+// List returns the active sessions of a user, dropping the ones whose session data has expired.
+func (s *SessionRegistry) List(ctx context.Context, userId string) ([]SessionInfo, error) {
+	sessions, er1 := s.load(ctx, userId)
+	if er1 != nil {
+		return nil, er1
+	}
+	active := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		data, er2 := s.Store.Get(ctx, session.Id)
+		if er2 != nil && !IsCacheMiss(er2) {
+			return nil, er2
+		}
+		if er2 == nil && len(data) > 0 {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// View returns the active sessions of a user for display: each id is replaced by its handle, so the session ids never leave the server.
+func (s *SessionRegistry) View(ctx context.Context, userId string, currentSessionId string) ([]SessionInfo, error) {
+	sessions, err := s.List(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == currentSessionId
+		sessions[i].Id = SessionHandle(sessions[i].Id)
+	}
+	return sessions, nil
+}
+
+func (s *SessionRegistry) Revoke(ctx context.Context, userId string, sessionId string) (bool, error) {
+	return s.revoke(ctx, userId, func(id string) bool {
+		return id == sessionId
+	})
+}
+
+// RevokeHandle revokes the session whose handle, as returned by View, is the given one.
+func (s *SessionRegistry) RevokeHandle(ctx context.Context, userId string, handle string) (bool, error) {
+	return s.revoke(ctx, userId, func(id string) bool {
+		return SessionHandle(id) == handle
+	})
+}
+
+func (s *SessionRegistry) revoke(ctx context.Context, userId string, match func(string) bool) (bool, error) {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	for i, session := range sessions {
+		if match(session.Id) {
+			if _, err = s.Store.Remove(ctx, session.Id); err != nil {
+				return false, err
+			}
+			sessions = append(sessions[:i], sessions[i+1:]...)
+			return true, s.save(ctx, userId, sessions)
+		}
+	}
+	return false, nil
+}
+
+func (s *SessionRegistry) RevokeOthers(ctx context.Context, userId string, currentSessionId string) (int64, error) {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return -1, err
+	}
+	defer unlock()
+	sessions, err := s.List(ctx, userId)
+	if err != nil {
+		return -1, err
+	}
+	var count int64
+	kept := make([]SessionInfo, 0, 1)
+	for _, session := range sessions {
+		if session.Id == currentSessionId {
+			kept = append(kept, session)
+			continue
+		}
+		if _, err = s.Store.Remove(ctx, session.Id); err != nil {
+			return -1, err
+		}
+		count = count + 1
+	}
+	return count, s.save(ctx, userId, kept)
+}
+
+func (s *SessionRegistry) RevokeAll(ctx context.Context, userId string) (int64, error) {
+	return s.RevokeOthers(ctx, userId, "")
+}
+
+func (s *SessionRegistry) Rename(ctx context.Context, userId string, oldId string, newId string) error {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		if sessions[i].Id == oldId {
+			sessions[i].Id = newId
+			return s.save(ctx, userId, sessions)
+		}
+	}
+	return nil
+}
+
+// Touch updates the last-seen time of a session, at most once per TouchInterval.
+func (s *SessionRegistry) Touch(ctx context.Context, userId string, sessionId string) error {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range sessions {
+		if sessions[i].Id == sessionId {
+			if now.Sub(sessions[i].LastSeen) < s.TouchInterval {
+				return nil
+			}
+			sessions[i].LastSeen = now
+			return s.save(ctx, userId, sessions)
+		}
+	}
+	return nil
+}
+
+func (s *SessionRegistry) load(ctx context.Context, userId string) ([]SessionInfo, error) {
+	sessions := make([]SessionInfo, 0)
+	data, err := s.Store.Get(ctx, s.Prefix+userId)
+	if err != nil {
+		if IsCacheMiss(err) {
+			return sessions, nil
+		}
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err = json.Unmarshal([]byte(data), &sessions); err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
+
+func (s *SessionRegistry) save(ctx context.Context, userId string, sessions []SessionInfo) error {
+	if len(sessions) == 0 {
+		_, err := s.Store.Remove(ctx, s.Prefix+userId)
+		return err
+	}
+	return s.Store.Put(ctx, s.Prefix+userId, sessions, s.Expired)
+}
+
+// lock serializes the read-modify-write of a user's session list: in process with a mutex per user, and across
+// processes with a short-lived lock key when the store supports an atomic add.
+func (s *SessionRegistry) lock(ctx context.Context, userId string) (func(), error) {
+	unlock := s.locks.lock(userId)
+	adder, ok := s.Store.(CacheAdder)
+	if !ok {
+		return unlock, nil
+	}
+	key := "lock:" + s.Prefix + userId
+	deadline := time.Now().Add(sessionListLockTime)
+	for {
+		acquired, err := adder.Add(ctx, key, "1", sessionListLockTime)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		if acquired {
+			return func() {
+				s.Store.Remove(context.Background(), key)
+				unlock()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			unlock()
+			return nil, errors.New("timeout waiting for the session list of " + userId)
+		}
+		select {
+		case <-ctx.Done():
+			unlock()
+			return nil, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
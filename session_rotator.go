@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/core-go/authentication/random"
+)
+
+const (
+	SessionRotatedAt = "rotatedAt"
+	SessionRotate    = "rotate"
+	SessionStepUpAt  = "stepUpAt"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionRotator struct {
+	Store              SessionStore
+	Generate           func(ctx context.Context) (string, error)
+	Expired            time.Duration
+	Sessions           *SessionRegistry
+	PrefixSessionIndex string
+	Id                 string
+	SId                string
+	// AliasPrefix and Grace keep an old id pointing to its new id for a short time after a periodic rotation, so that
+	// parallel requests still carrying the old cookie, or rotating it at the same time, end up on the same new session.
+	AliasPrefix string
+	Grace       time.Duration
+	locks       keyLocks
+}
+
+func NewSessionRotator(store SessionStore, generate func(ctx context.Context) (string, error), expired time.Duration, sessions *SessionRegistry, options ...string) *SessionRotator {
+	if store == nil {
+		panic(errors.New("store cannot be nil"))
+	}
+	var prefixSessionIndex, id, sid string
+	if len(options) > 0 {
+		prefixSessionIndex = options[0]
+	} else {
+		prefixSessionIndex = "index:"
+	}
+	if len(options) > 1 {
+		id = options[1]
+	} else {
+		id = "id"
+	}
+	if len(options) > 2 {
+		sid = options[2]
+	} else {
+		sid = "sid"
+	}
+	if generate == nil {
+		generate = random.SessionId
+	}
+	return &SessionRotator{Store: store, Generate: generate, Expired: expired, Sessions: sessions, PrefixSessionIndex: prefixSessionIndex, Id: id, SId: sid, AliasPrefix: "rotated:", Grace: 30 * time.Second}
+}
+
+// Rotate moves the session data to a new id and invalidates the old one, returning the new id.
+// Rotating an id that was periodically rotated within Grace returns the same new id; a session flagged by
+// RequireRotation gets no alias, so its old id stops working at once.
+func (r *SessionRotator) Rotate(ctx context.Context, sessionId string) (string, error) {
+	return r.rotate(ctx, sessionId, nil)
+}
+
+// StepUp rotates the session after the user re-authenticates on it, such as a password confirmation, a second factor
+// or a WebAuthn assertion, and records the time in SessionStepUpAt; the caller sets the returned id in the cookie.
+// The old id gets no alias: whoever still holds it must not reach the stepped up session.
+func (r *SessionRotator) StepUp(ctx context.Context, sessionId string) (string, error) {
+	return r.rotate(ctx, sessionId, func(data map[string]interface{}) {
+		data[SessionStepUpAt] = time.Now().Format(time.RFC3339)
+	})
+}
+
+// Resolve returns the id an old session id was rotated to, while the alias lasts.
+func (r *SessionRotator) Resolve(ctx context.Context, sessionId string) (string, bool, error) {
+	newId, err := r.Store.Get(ctx, r.AliasPrefix+sessionId)
+	if err != nil {
+		if IsCacheMiss(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return newId, len(newId) > 0, nil
+}
+
+func (r *SessionRotator) rotate(ctx context.Context, sessionId string, update func(map[string]interface{})) (string, error) {
+	unlock := r.locks.lock(sessionId)
+	defer unlock()
+	if newId, ok, er0 := r.Resolve(ctx, sessionId); er0 != nil || ok {
+		return newId, er0
+	}
+	data, er1 := r.load(ctx, sessionId)
+	if er1 != nil {
+		if er1 == ErrSessionNotFound {
+			// another instance may have rotated it between the two reads
+			if newId, ok, er0 := r.Resolve(ctx, sessionId); er0 != nil || ok {
+				return newId, er0
+			}
+		}
+		return "", er1
+	}
+	alias := update == nil && data[SessionRotate] != "true"
+	newId, er2 := r.Generate(ctx)
+	if er2 != nil {
+		return "", er2
+	}
+	data[SessionRotatedAt] = time.Now().Format(time.RFC3339)
+	delete(data, SessionRotate)
+	if update != nil {
+		update(data)
+	}
+	if er3 := r.Store.Put(ctx, newId, data, r.Expired); er3 != nil {
+		return "", er3
+	}
+	// the alias is the claim on the old id: with an atomic add only one rotation wins, and the others drop their copy;
+	// without an alias, the claim is kept empty, so it never resolves to the new id
+	target := ""
+	if alias {
+		target = newId
+	}
+	if adder, ok := r.Store.(CacheAdder); ok {
+		claimed, er4 := adder.Add(ctx, r.AliasPrefix+sessionId, target, r.grace())
+		if er4 != nil {
+			return "", er4
+		}
+		if !claimed {
+			if _, er5 := r.Store.Remove(ctx, newId); er5 != nil {
+				return "", er5
+			}
+			winner, ok, er6 := r.Resolve(ctx, sessionId)
+			if er6 == nil && !ok {
+				er6 = ErrSessionNotFound
+			}
+			return winner, er6
+		}
+	} else if alias {
+		if er4 := r.Store.Put(ctx, r.AliasPrefix+sessionId, newId, r.grace()); er4 != nil {
+			return "", er4
+		}
+	}
+	if _, er5 := r.Store.Remove(ctx, sessionId); er5 != nil {
+		return "", er5
+	}
+	userId, _ := data[r.Id].(string)
+	if len(userId) == 0 {
+		return newId, nil
+	}
+	if len(r.PrefixSessionIndex) > 0 {
+		index, er7 := r.load(ctx, r.PrefixSessionIndex+userId)
+		if er7 != nil && er7 != ErrSessionNotFound {
+			return "", er7
+		}
+		if er7 == nil && index[r.SId] == sessionId {
+			index[r.SId] = newId
+			if er8 := r.Store.Put(ctx, r.PrefixSessionIndex+userId, index, r.Expired); er8 != nil {
+				return "", er8
+			}
+		}
+	}
+	if r.Sessions != nil {
+		if er9 := r.Sessions.Rename(ctx, userId, sessionId, newId); er9 != nil {
+			return "", er9
+		}
+	}
+	return newId, nil
+}
+
+// RequireRotation flags all active sessions of a user, so that they get a new id on their next request; used after role changes.
+func (r *SessionRotator) RequireRotation(ctx context.Context, userId string) (int64, error) {
+	if r.Sessions == nil {
+		return -1, errors.New("session registry is not configured")
+	}
+	sessions, err := r.Sessions.List(ctx, userId)
+	if err != nil {
+		return -1, err
+	}
+	var count int64
+	for _, session := range sessions {
+		data, er1 := r.load(ctx, session.Id)
+		if er1 == ErrSessionNotFound {
+			continue
+		}
+		if er1 != nil {
+			return count, er1
+		}
+		data[SessionRotate] = "true"
+		if er2 := r.Store.Put(ctx, session.Id, data, r.Expired); er2 != nil {
+			return count, er2
+		}
+		count = count + 1
+	}
+	return count, nil
+}
+
+func (r *SessionRotator) grace() time.Duration {
+	if r.Grace > 0 {
+		return r.Grace
+	}
+	return 30 * time.Second
+}
+
+func (r *SessionRotator) load(ctx context.Context, key string) (map[string]interface{}, error) {
+	s, err := r.Store.Get(ctx, key)
+	if err != nil {
+		if IsCacheMiss(err) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	if len(s) == 0 {
+		return nil, ErrSessionNotFound
+	}
+	data := make(map[string]interface{})
+	if err = json.Unmarshal([]byte(s), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// NeedRotation reports whether a session must get a new id, because it was flagged or its id is older than interval.
+func NeedRotation(data map[string]string, interval time.Duration, now time.Time) bool {
+	if data[SessionRotate] == "true" {
+		return true
+	}
+	if interval <= 0 {
+		return false
+	}
+	rotatedAt, err := time.Parse(time.RFC3339, data[SessionRotatedAt])
+	if err != nil {
+		return true
+	}
+	return now.Sub(rotatedAt) >= interval
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SlidingWindow struct {
+	Store  RateLimitStore
+	Limit  int
+	Window time.Duration
+	locks  keyLocks
+}
+
+func NewSlidingWindow(store RateLimitStore, limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{Store: store, Limit: limit, Window: window}
+}
+
+func (w *SlidingWindow) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	if w.Limit <= 0 || w.Window <= 0 {
+		return true, 0, nil
+	}
+	unlock := w.locks.lock(key)
+	defer unlock()
+	now := time.Now().UnixNano()
+	window := int64(w.Window)
+	start := now - now%window
+	state, err := w.Store.Get(ctx, key)
+	if err != nil {
+		return false, 0, err
+	}
+	var previous, current int64
+	if parts := strings.Split(state, "|"); len(parts) == 3 {
+		s, er1 := strconv.ParseInt(parts[0], 10, 64)
+		p, er2 := strconv.ParseInt(parts[1], 10, 64)
+		c, er3 := strconv.ParseInt(parts[2], 10, 64)
+		if er1 == nil && er2 == nil && er3 == nil {
+			if s == start {
+				previous, current = p, c
+			} else if s == start-window {
+				previous = c
+			}
+		}
+	}
+	elapsed := now - start
+	weight := float64(window-elapsed) / float64(window)
+	limit := int64(w.Limit)
+	if float64(previous)*weight+float64(current)+1 > float64(limit) {
+		var retryAfter int64
+		if current+1 > limit || previous == 0 {
+			retryAfter = window - elapsed
+		} else {
+			t := float64(window) * (1 - float64(limit-1-current)/float64(previous))
+			retryAfter = int64(t) - elapsed
+			if retryAfter <= 0 {
+				retryAfter = 1
+			}
+		}
+		return false, time.Duration(retryAfter), nil
+	}
+	current = current + 1
+	value := strconv.FormatInt(start, 10) + "|" + strconv.FormatInt(previous, 10) + "|" + strconv.FormatInt(current, 10)
+	if err = w.Store.Put(ctx, key, value, 2*w.Window); err != nil {
+		return false, 0, err
+	}
+	return true, 0, nil
+}
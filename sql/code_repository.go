@@ -0,0 +1,154 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type CodeRepository struct {
+	DB        *sql.DB
+	Table     string
+	Id        string
+	Code      string
+	ExpiredAt string
+	Attempts  string
+	Driver    string
+	Param     func(int) string
+}
+
+func NewCodeAdapter(db *sql.DB, table string, options ...string) *CodeRepository {
+	return NewCodeRepository(db, table, options...)
+}
+func NewCodeRepository(db *sql.DB, table string, options ...string) *CodeRepository {
+	var id, code, expiredAt, attempts string
+	if len(options) > 0 && len(options[0]) > 0 {
+		id = options[0]
+	} else {
+		id = "id"
+	}
+	if len(options) > 1 && len(options[1]) > 0 {
+		code = options[1]
+	} else {
+		code = "code"
+	}
+	if len(options) > 2 && len(options[2]) > 0 {
+		expiredAt = options[2]
+	} else {
+		expiredAt = "expiredat"
+	}
+	if len(options) > 3 && len(options[3]) > 0 {
+		attempts = options[3]
+	} else {
+		attempts = "attempts"
+	}
+	if len(table) == 0 {
+		table = "passcodes"
+	}
+	driver := getDriver(db)
+	return &CodeRepository{DB: db, Table: table, Id: id, Code: code, ExpiredAt: expiredAt, Attempts: attempts, Driver: driver, Param: GetBuildByDriver(driver)}
+}
+
+func (r *CodeRepository) Save(ctx context.Context, id string, code string, expireAt time.Time) (int64, error) {
+	count, er1 := r.update(ctx, id, code, expireAt)
+	if er1 != nil || count > 0 {
+		return count, er1
+	}
+	query := fmt.Sprintf("insert into %s (%s, %s, %s, %s) values (%s, %s, %s, %s)", r.Table, r.Id, r.Code, r.ExpiredAt, r.Attempts, r.Param(1), r.Param(2), r.Param(3), r.Param(4))
+	res, er2 := r.DB.ExecContext(ctx, query, id, code, expireAt, 0)
+	if er2 != nil {
+		// the row may have been inserted concurrently after the update, so try the update once more
+		count, er3 := r.update(ctx, id, code, expireAt)
+		if er3 != nil || count == 0 {
+			return 0, er2
+		}
+		return count, nil
+	}
+	return res.RowsAffected()
+}
+func (r *CodeRepository) update(ctx context.Context, id string, code string, expireAt time.Time) (int64, error) {
+	query := fmt.Sprintf("update %s set %s = %s, %s = %s, %s = 0 where %s = %s", r.Table, r.Code, r.Param(1), r.ExpiredAt, r.Param(2), r.Attempts, r.Id, r.Param(3))
+	res, err := r.DB.ExecContext(ctx, query, code, expireAt, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *CodeRepository) Load(ctx context.Context, id string) (string, time.Time, error) {
+	query := fmt.Sprintf("select %s, %s from %s where %s = %s", r.Code, r.ExpiredAt, r.Table, r.Id, r.Param(1))
+	var code sql.NullString
+	var expiredAt sql.NullTime
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(&code, &expiredAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, err
+	}
+	if !expiredAt.Valid || expiredAt.Time.Before(time.Now()) {
+		return "", time.Time{}, nil
+	}
+	return code.String, expiredAt.Time, nil
+}
+
+// IncreaseAttempts returns the new count from the update itself where the driver supports it; otherwise the update and
+// the read run in one transaction, so the row stays locked until the count is read.
+func (r *CodeRepository) IncreaseAttempts(ctx context.Context, id string) (int, error) {
+	switch r.Driver {
+	case driverPostgres, driverSqlite3:
+		query := fmt.Sprintf("update %s set %s = %s + 1 where %s = %s returning %s", r.Table, r.Attempts, r.Attempts, r.Id, r.Param(1), r.Attempts)
+		return r.scanAttempts(r.DB.QueryRowContext(ctx, query, id))
+	case driverMssql:
+		query := fmt.Sprintf("update %s set %s = %s + 1 output inserted.%s where %s = %s", r.Table, r.Attempts, r.Attempts, r.Attempts, r.Id, r.Param(1))
+		return r.scanAttempts(r.DB.QueryRowContext(ctx, query, id))
+	}
+	tx, er1 := r.DB.BeginTx(ctx, nil)
+	if er1 != nil {
+		return 0, er1
+	}
+	defer tx.Rollback()
+	query := fmt.Sprintf("update %s set %s = %s + 1 where %s = %s", r.Table, r.Attempts, r.Attempts, r.Id, r.Param(1))
+	res, er2 := tx.ExecContext(ctx, query, id)
+	if er2 != nil {
+		return 0, er2
+	}
+	if count, er3 := res.RowsAffected(); er3 != nil || count == 0 {
+		return 0, er3
+	}
+	query = fmt.Sprintf("select %s from %s where %s = %s", r.Attempts, r.Table, r.Id, r.Param(1))
+	attempts, er4 := r.scanAttempts(tx.QueryRowContext(ctx, query, id))
+	if er4 != nil {
+		return 0, er4
+	}
+	return attempts, tx.Commit()
+}
+func (r *CodeRepository) scanAttempts(row *sql.Row) (int, error) {
+	var attempts int
+	if err := row.Scan(&attempts); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return attempts, nil
+}
+
+func (r *CodeRepository) Delete(ctx context.Context, id string) (int64, error) {
+	query := fmt.Sprintf("delete from %s where %s = %s", r.Table, r.Id, r.Param(1))
+	res, err := r.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *CodeRepository) Purge(ctx context.Context) (int64, error) {
+	query := fmt.Sprintf("delete from %s where %s < %s", r.Table, r.ExpiredAt, r.Param(1))
+	res, err := r.DB.ExecContext(ctx, query, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
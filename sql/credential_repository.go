@@ -0,0 +1,79 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	a "github.com/core-go/authentication"
+)
+
+const credentialColumns = "id, user_id, public_key, algorithm, sign_count, aaguid, transports, name, created_time, last_used_time"
+
+type CredentialRepository struct {
+	DB     *sql.DB
+	Table  string
+	Driver string
+	Param  func(int) string
+	fields map[string]int
+}
+
+func NewCredentialAdapter(db *sql.DB, table string) (*CredentialRepository, error) {
+	return NewCredentialRepository(db, table)
+}
+func NewCredentialRepository(db *sql.DB, table string) (*CredentialRepository, error) {
+	driver := getDriver(db)
+	var credential a.WebAuthnCredential
+	fields, err := getColumnIndexes(reflect.TypeOf(credential))
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialRepository{DB: db, Table: table, Driver: driver, Param: GetBuildByDriver(driver), fields: fields}, nil
+}
+
+func (r *CredentialRepository) Load(ctx context.Context, userId string) ([]a.WebAuthnCredential, error) {
+	var credentials []a.WebAuthnCredential
+	query := fmt.Sprintf("select %s from %s where user_id = %s", credentialColumns, r.Table, r.Param(1))
+	_, err := queryWithMap(ctx, r.DB, r.fields, &credentials, query, userId)
+	return credentials, err
+}
+func (r *CredentialRepository) Get(ctx context.Context, id string) (*a.WebAuthnCredential, error) {
+	var credentials []a.WebAuthnCredential
+	query := fmt.Sprintf("select %s from %s where id = %s", credentialColumns, r.Table, r.Param(1))
+	_, err := queryWithMap(ctx, r.DB, r.fields, &credentials, query, id)
+	if err != nil || len(credentials) == 0 {
+		return nil, err
+	}
+	return &credentials[0], nil
+}
+func (r *CredentialRepository) Save(ctx context.Context, c a.WebAuthnCredential) (int64, error) {
+	params := make([]string, 0)
+	for i := 1; i <= 10; i++ {
+		params = append(params, r.Param(i))
+	}
+	query := fmt.Sprintf("insert into %s (%s) values (%s)", r.Table, credentialColumns, strings.Join(params, ","))
+	res, err := r.DB.ExecContext(ctx, query, c.Id, c.UserId, c.PublicKey, c.Algorithm, c.SignCount, c.AAGUID, c.Transports, c.Name, c.CreatedTime, c.LastUsedTime)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+func (r *CredentialRepository) Update(ctx context.Context, id string, signCount int64, lastUsedTime time.Time) (int64, error) {
+	query := fmt.Sprintf("update %s set sign_count = %s, last_used_time = %s where id = %s", r.Table, r.Param(1), r.Param(2), r.Param(3))
+	res, err := r.DB.ExecContext(ctx, query, signCount, lastUsedTime, id)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+func (r *CredentialRepository) Delete(ctx context.Context, userId string, id string) (int64, error) {
+	query := fmt.Sprintf("delete from %s where user_id = %s and id = %s", r.Table, r.Param(1), r.Param(2))
+	res, err := r.DB.ExecContext(ctx, query, userId, id)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,57 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	a "github.com/core-go/authentication"
+)
+
+type EventSink struct {
+	DB     *sql.DB
+	Table  string
+	Config EventConfig
+	Param  func(int) string
+}
+
+func NewEventWriter(db *sql.DB, table string, options ...EventConfig) *EventSink {
+	return NewEventSink(db, table, options...)
+}
+func NewEventSink(db *sql.DB, table string, options ...EventConfig) *EventSink {
+	var c EventConfig
+	if len(options) > 0 {
+		c = options[0]
+	} else {
+		c = EventConfig{Type: "type", UserId: "userid", Username: "username", Ip: "ip", Device: "device", Provider: "provider", Status: "status", Reason: "reason", Time: "time"}
+	}
+	return &EventSink{DB: db, Table: table, Config: c, Param: GetBuildByDriver(getDriver(db))}
+}
+
+func (s *EventSink) Write(ctx context.Context, event a.AuthEvent) error {
+	cols := make([]string, 0)
+	params := make([]interface{}, 0)
+	add := func(col string, value interface{}) {
+		if len(col) > 0 {
+			cols = append(cols, col)
+			params = append(params, value)
+		}
+	}
+	add(s.Config.Type, event.Type)
+	add(s.Config.UserId, event.UserId)
+	add(s.Config.Username, event.Username)
+	add(s.Config.Ip, event.Ip)
+	add(s.Config.Device, event.Device)
+	add(s.Config.Provider, event.Provider)
+	add(s.Config.Status, event.Status)
+	add(s.Config.Reason, event.Reason)
+	add(s.Config.Time, event.Time)
+	values := make([]string, len(cols))
+	for i := range cols {
+		values[i] = s.Param(i + 1)
+	}
+	query := fmt.Sprintf("insert into %s (%s) values (%s)", s.Table, strings.Join(cols, ","), strings.Join(values, ","))
+	_, err := s.DB.ExecContext(ctx, query, params...)
+	return err
+}
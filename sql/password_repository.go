@@ -0,0 +1,165 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	a "github.com/core-go/authentication"
+)
+
+type PasswordRepository struct {
+	DB            *sql.DB
+	UserTable     string
+	PasswordTable string
+	HistoryTable  string
+	Conf          a.SchemaConfig
+	HistoryUserId string
+	HistoryTime   string
+	Param         func(int) string
+	userFields    map[string]int
+}
+
+func NewPasswordAdapter(db *sql.DB, userTable, passwordTable, historyTable string, conf a.SchemaConfig) (*PasswordRepository, error) {
+	return NewPasswordRepository(db, userTable, passwordTable, historyTable, conf)
+}
+func NewPasswordRepository(db *sql.DB, userTable, passwordTable, historyTable string, conf a.SchemaConfig) (*PasswordRepository, error) {
+	if len(passwordTable) == 0 {
+		passwordTable = userTable
+	}
+	if len(conf.Id) == 0 {
+		conf.Id = "id"
+	}
+	if len(conf.Username) == 0 {
+		conf.Username = "username"
+	}
+	if len(conf.Password) == 0 {
+		conf.Password = "password"
+	}
+	historyUserId := conf.UserId
+	if len(historyUserId) == 0 {
+		historyUserId = "userid"
+	}
+	historyTime := conf.PasswordChangedTime
+	if len(historyTime) == 0 {
+		historyTime = "passwordchangedtime"
+	}
+	var user a.UserInfo
+	userFields, err := getColumnIndexes(reflect.TypeOf(user))
+	if err != nil {
+		return nil, err
+	}
+	return &PasswordRepository{DB: db, UserTable: userTable, PasswordTable: passwordTable, HistoryTable: historyTable, Conf: conf, HistoryUserId: historyUserId, HistoryTime: historyTime, Param: GetBuildByDriver(getDriver(db)), userFields: userFields}, nil
+}
+
+func (r *PasswordRepository) GetUserByContact(ctx context.Context, contact string) (*a.UserInfo, error) {
+	cols := []string{fmt.Sprintf("%s as id", r.Conf.Id), fmt.Sprintf("%s as username", r.Conf.Username)}
+	where := fmt.Sprintf("%s = %s", r.Conf.Username, r.Param(1))
+	params := []interface{}{contact}
+	if len(r.Conf.Email) > 0 {
+		cols = append(cols, fmt.Sprintf("%s as email", r.Conf.Email))
+		where = where + fmt.Sprintf(" or %s = %s", r.Conf.Email, r.Param(2))
+		params = append(params, contact)
+	}
+	if len(r.Conf.Contact) > 0 {
+		cols = append(cols, fmt.Sprintf("%s as contact", r.Conf.Contact))
+	}
+	if len(r.Conf.DisplayName) > 0 {
+		cols = append(cols, fmt.Sprintf("%s as display_name", r.Conf.DisplayName))
+	}
+	if len(r.Conf.Language) > 0 {
+		cols = append(cols, fmt.Sprintf("%s as language", r.Conf.Language))
+	}
+	if r.PasswordTable == r.UserTable {
+		cols = append(cols, fmt.Sprintf("%s as password", r.Conf.Password))
+	}
+	var users []a.UserInfo
+	query := fmt.Sprintf("select %s from %s where %s", strings.Join(cols, ","), r.UserTable, where)
+	_, err := queryWithMap(ctx, r.DB, r.userFields, &users, query, params...)
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	user := users[0]
+	if r.PasswordTable != r.UserTable {
+		var passwords []a.UserInfo
+		query = fmt.Sprintf("select %s as password from %s where %s = %s", r.Conf.Password, r.PasswordTable, r.Conf.Id, r.Param(1))
+		_, err = queryWithMap(ctx, r.DB, r.userFields, &passwords, query, user.Id)
+		if err != nil {
+			return nil, err
+		}
+		if len(passwords) > 0 {
+			user.Password = passwords[0].Password
+		}
+	}
+	return &user, nil
+}
+
+func (r *PasswordRepository) GetHistory(ctx context.Context, id string, max int) ([]string, error) {
+	passwords := make([]string, 0)
+	if len(r.HistoryTable) == 0 || max <= 0 {
+		return passwords, nil
+	}
+	query := fmt.Sprintf("select %s from %s where %s = %s order by %s desc", r.Conf.Password, r.HistoryTable, r.HistoryUserId, r.Param(1), r.HistoryTime)
+	rows, err := r.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() && len(passwords) < max {
+		var password string
+		if err = rows.Scan(&password); err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, password)
+	}
+	return passwords, rows.Err()
+}
+
+func (r *PasswordRepository) Update(ctx context.Context, id string, newPassword string) (int64, error) {
+	now := time.Now()
+	i := 1
+	cols := []string{fmt.Sprintf("%s = %s", r.Conf.Password, r.Param(i))}
+	params := []interface{}{newPassword}
+	i = i + 1
+	if len(r.Conf.PasswordChangedTime) > 0 {
+		cols = append(cols, fmt.Sprintf("%s = %s", r.Conf.PasswordChangedTime, r.Param(i)))
+		params = append(params, now)
+		i = i + 1
+	}
+	if len(r.Conf.FailCount) > 0 {
+		cols = append(cols, fmt.Sprintf("%s = 0", r.Conf.FailCount))
+	}
+	if len(r.Conf.LockedUntilTime) > 0 {
+		cols = append(cols, fmt.Sprintf("%s = null", r.Conf.LockedUntilTime))
+	}
+	params = append(params, id)
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	query := fmt.Sprintf("update %s set %s where %s = %s", r.PasswordTable, strings.Join(cols, ","), r.Conf.Id, r.Param(i))
+	res, err := tx.ExecContext(ctx, query, params...)
+	if err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+	count, err := res.RowsAffected()
+	if err != nil || count <= 0 {
+		tx.Rollback()
+		return count, err
+	}
+	if len(r.HistoryTable) > 0 {
+		query = fmt.Sprintf("insert into %s (%s, %s, %s) values (%s, %s, %s)", r.HistoryTable, r.HistoryUserId, r.Conf.Password, r.HistoryTime, r.Param(1), r.Param(2), r.Param(3))
+		if _, err = tx.ExecContext(ctx, query, id, newPassword, now); err != nil {
+			tx.Rollback()
+			return -1, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return -1, err
+	}
+	return count, nil
+}
@@ -0,0 +1,139 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	a "github.com/core-go/authentication"
+)
+
+type RoleRepository struct {
+	DB              *sql.DB
+	RoleTable       string
+	RoleModuleTable string
+	UserRoleTable   string
+	Driver          string
+	Param           func(int) string
+}
+
+func NewRoleAdapter(db *sql.DB, roleTable, roleModuleTable, userRoleTable string) *RoleRepository {
+	return NewRoleRepository(db, roleTable, roleModuleTable, userRoleTable)
+}
+func NewRoleRepository(db *sql.DB, roleTable, roleModuleTable, userRoleTable string) *RoleRepository {
+	if len(roleTable) == 0 {
+		roleTable = "roles"
+	}
+	if len(roleModuleTable) == 0 {
+		roleModuleTable = "rolemodules"
+	}
+	if len(userRoleTable) == 0 {
+		userRoleTable = "userroles"
+	}
+	driver := getDriver(db)
+	return &RoleRepository{DB: db, RoleTable: roleTable, RoleModuleTable: roleModuleTable, UserRoleTable: userRoleTable, Driver: driver, Param: GetBuildByDriver(driver)}
+}
+
+func (r *RoleRepository) All(ctx context.Context) ([]a.Role, error) {
+	query := fmt.Sprintf("select id, name, inherits from %s order by id", r.RoleTable)
+	rows, er1 := r.DB.QueryContext(ctx, query)
+	if er1 != nil {
+		return nil, er1
+	}
+	defer rows.Close()
+	roles := make([]a.Role, 0)
+	index := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var name, inherits sql.NullString
+		if er2 := rows.Scan(&id, &name, &inherits); er2 != nil {
+			return nil, er2
+		}
+		role := a.Role{Id: id, Name: name.String}
+		if len(inherits.String) > 0 {
+			for _, parent := range strings.Split(inherits.String, ",") {
+				if parent = strings.TrimSpace(parent); len(parent) > 0 {
+					role.Inherits = append(role.Inherits, parent)
+				}
+			}
+		}
+		index[id] = len(roles)
+		roles = append(roles, role)
+	}
+	if er3 := rows.Err(); er3 != nil {
+		return nil, er3
+	}
+	query2 := fmt.Sprintf("select roleid, moduleid, permissions from %s", r.RoleModuleTable)
+	rows2, er4 := r.DB.QueryContext(ctx, query2)
+	if er4 != nil {
+		return nil, er4
+	}
+	defer rows2.Close()
+	for rows2.Next() {
+		var roleId, moduleId string
+		var permissions sql.NullInt64
+		if er5 := rows2.Scan(&roleId, &moduleId, &permissions); er5 != nil {
+			return nil, er5
+		}
+		if i, ok := index[roleId]; ok {
+			roles[i].Modules = append(roles[i].Modules, a.RoleModule{ModuleId: moduleId, Permissions: int32(permissions.Int64)})
+		}
+	}
+	return roles, rows2.Err()
+}
+
+func (r *RoleRepository) GetUserRoles(ctx context.Context, userId string) ([]string, error) {
+	query := fmt.Sprintf("select roleid from %s where userid = %s", r.UserRoleTable, r.Param(1))
+	rows, er1 := r.DB.QueryContext(ctx, query, userId)
+	if er1 != nil {
+		return nil, er1
+	}
+	defer rows.Close()
+	roles := make([]string, 0)
+	for rows.Next() {
+		var roleId string
+		if er2 := rows.Scan(&roleId); er2 != nil {
+			return nil, er2
+		}
+		roles = append(roles, roleId)
+	}
+	return roles, rows.Err()
+}
+
+// AssignRole inserts the pair in one statement that skips an existing row, so concurrent calls cannot add duplicates.
+// For postgres, mysql and sqlite the user role table must have a unique key on (userid, roleid).
+func (r *RoleRepository) AssignRole(ctx context.Context, userId string, roleId string) (int64, error) {
+	var query string
+	args := []interface{}{userId, roleId}
+	switch r.Driver {
+	case driverPostgres:
+		query = fmt.Sprintf("insert into %s (userid, roleid) values (%s, %s) on conflict do nothing", r.UserRoleTable, r.Param(1), r.Param(2))
+	case driverMysql:
+		query = fmt.Sprintf("insert ignore into %s (userid, roleid) values (%s, %s)", r.UserRoleTable, r.Param(1), r.Param(2))
+	case driverSqlite3:
+		query = fmt.Sprintf("insert or ignore into %s (userid, roleid) values (%s, %s)", r.UserRoleTable, r.Param(1), r.Param(2))
+	case driverMssql:
+		query = fmt.Sprintf("insert into %s (userid, roleid) select %s, %s where not exists (select 1 from %s with (updlock, holdlock) where userid = %s and roleid = %s)", r.UserRoleTable, r.Param(1), r.Param(2), r.UserRoleTable, r.Param(3), r.Param(4))
+		args = append(args, userId, roleId)
+	case driverOracle:
+		query = fmt.Sprintf("merge into %s u using (select %s userid, %s roleid from dual) s on (u.userid = s.userid and u.roleid = s.roleid) when not matched then insert (userid, roleid) values (s.userid, s.roleid)", r.UserRoleTable, r.Param(1), r.Param(2))
+	default:
+		query = fmt.Sprintf("insert into %s (userid, roleid) select %s, %s where not exists (select 1 from %s where userid = %s and roleid = %s)", r.UserRoleTable, r.Param(1), r.Param(2), r.UserRoleTable, r.Param(3), r.Param(4))
+		args = append(args, userId, roleId)
+	}
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *RoleRepository) RevokeRole(ctx context.Context, userId string, roleId string) (int64, error) {
+	query := fmt.Sprintf("delete from %s where userid = %s and roleid = %s", r.UserRoleTable, r.Param(1), r.Param(2))
+	res, err := r.DB.ExecContext(ctx, query, userId, roleId)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
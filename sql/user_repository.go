@@ -51,7 +51,17 @@ func NewUserRepository(db *sql.DB, query string, conf DBConfig, status a.UserSta
 }
 func (l SqlUserRepository) GetUser(ctx context.Context, username string) (*a.UserInfo, error) {
 	var models []a.UserInfo
-	_, err := queryWithMap(ctx, l.DB, l.userFields, &models, l.Query, username)
+	query := l.Query
+	params := []interface{}{username}
+	if len(l.Conf.Tenant) > 0 {
+		tenant := a.GetTenant(ctx)
+		if len(tenant) == 0 {
+			return nil, nil
+		}
+		query = fmt.Sprintf("select * from (%s) u where u.%s = %s", query, l.Conf.Tenant, l.buildParam(2))
+		params = append(params, tenant)
+	}
+	_, err := queryWithMap(ctx, l.DB, l.userFields, &models, query, params...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +89,12 @@ func (l SqlUserRepository) GetUser(ctx context.Context, username string) (*a.Use
 	}
 	return nil, nil
 }
+func (l SqlUserRepository) buildParam(i int) string {
+	if l.Param == nil {
+		return "?"
+	}
+	return l.Param(i)
+}
 func (l SqlUserRepository) Pass(ctx context.Context, id string, deactivated *bool) error {
 	if len(l.Conf.User) == 0 && len(l.Conf.Password) == 0 {
 		return nil
@@ -160,7 +176,7 @@ func (l SqlUserRepository) Fail(ctx context.Context, id string, failCount *int,
 		cols = append(cols, fmt.Sprintf("%s=%d", l.Conf.FailCount, count))
 	}
 	if len(l.Conf.LockedUntilTime) > 0 && lockedUntilTime != nil {
-		cols = append(cols, fmt.Sprintf("%s=%s", l.Conf.FailCount, l.Param(i)))
+		cols = append(cols, fmt.Sprintf("%s=%s", l.Conf.LockedUntilTime, l.Param(i)))
 		params = append(params, lockedUntilTime)
 		i = i + 1
 	}
@@ -172,3 +188,14 @@ func (l SqlUserRepository) Fail(ctx context.Context, id string, failCount *int,
 	_, err := l.DB.ExecContext(ctx, sql, params...)
 	return err
 }
+func (l SqlUserRepository) UpdatePassword(ctx context.Context, id string, password string) (int64, error) {
+	if len(l.Conf.PasswordColumn) == 0 {
+		return 0, nil
+	}
+	sql := fmt.Sprintf(`update %s set %s = %s where %s = %s`, l.Conf.Password, l.Conf.PasswordColumn, l.Param(1), l.Conf.Id, l.Param(2))
+	res, err := l.DB.ExecContext(ctx, sql, password, id)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
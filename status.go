@@ -13,6 +13,9 @@ type StatusConfig struct {
 	Locked                *int `yaml:"locked" mapstructure:"locked" json:"locked,omitempty" gorm:"column:locked" bson:"locked,omitempty" dynamodbav:"locked,omitempty" firestore:"locked,omitempty"`
 	Suspended             *int `yaml:"suspended" mapstructure:"suspended" json:"suspended,omitempty" gorm:"column:suspended" bson:"suspended,omitempty" dynamodbav:"suspended,omitempty" firestore:"suspended,omitempty"`
 	Disabled              *int `yaml:"disabled" mapstructure:"disabled" json:"disabled,omitempty" gorm:"column:disabled" bson:"disabled,omitempty" dynamodbav:"disabled,omitempty" firestore:"disabled,omitempty"`
+	TooManyRequests       *int `yaml:"too_many_requests" mapstructure:"too_many_requests" json:"tooManyRequests,omitempty" gorm:"column:toomanyrequests" bson:"tooManyRequests,omitempty" dynamodbav:"tooManyRequests,omitempty" firestore:"tooManyRequests,omitempty"`
+	PasscodeInvalid       *int `yaml:"passcode_invalid" mapstructure:"passcode_invalid" json:"passcodeInvalid,omitempty" gorm:"column:passcodeinvalid" bson:"passcodeInvalid,omitempty" dynamodbav:"passcodeInvalid,omitempty" firestore:"passcodeInvalid,omitempty"`
+	PasscodeExhausted     *int `yaml:"passcode_exhausted" mapstructure:"passcode_exhausted" json:"passcodeExhausted,omitempty" gorm:"column:passcodeexhausted" bson:"passcodeExhausted,omitempty" dynamodbav:"passcodeExhausted,omitempty" firestore:"passcodeExhausted,omitempty"`
 	Error                 *int `yaml:"error" mapstructure:"error" json:"error,omitempty" gorm:"column:error" bson:"error,omitempty" dynamodbav:"error,omitempty" firestore:"error,omitempty"`
 }
 type Status struct {
@@ -28,6 +31,9 @@ type Status struct {
 	Locked                int `yaml:"locked" mapstructure:"locked" json:"locked,omitempty" gorm:"column:locked" bson:"locked,omitempty" dynamodbav:"locked,omitempty" firestore:"locked,omitempty"`
 	Suspended             int `yaml:"suspended" mapstructure:"suspended" json:"suspended,omitempty" gorm:"column:suspended" bson:"suspended,omitempty" dynamodbav:"suspended,omitempty" firestore:"suspended,omitempty"`
 	Disabled              int `yaml:"disabled" mapstructure:"disabled" json:"disabled,omitempty" gorm:"column:disabled" bson:"disabled,omitempty" dynamodbav:"disabled,omitempty" firestore:"disabled,omitempty"`
+	TooManyRequests       int `yaml:"too_many_requests" mapstructure:"too_many_requests" json:"tooManyRequests,omitempty" gorm:"column:toomanyrequests" bson:"tooManyRequests,omitempty" dynamodbav:"tooManyRequests,omitempty" firestore:"tooManyRequests,omitempty"`
+	PasscodeInvalid       int `yaml:"passcode_invalid" mapstructure:"passcode_invalid" json:"passcodeInvalid,omitempty" gorm:"column:passcodeinvalid" bson:"passcodeInvalid,omitempty" dynamodbav:"passcodeInvalid,omitempty" firestore:"passcodeInvalid,omitempty"`
+	PasscodeExhausted     int `yaml:"passcode_exhausted" mapstructure:"passcode_exhausted" json:"passcodeExhausted,omitempty" gorm:"column:passcodeexhausted" bson:"passcodeExhausted,omitempty" dynamodbav:"passcodeExhausted,omitempty" firestore:"passcodeExhausted,omitempty"`
 	Error                 int `yaml:"error" mapstructure:"error" json:"error,omitempty" gorm:"column:error" bson:"error,omitempty" dynamodbav:"error,omitempty" firestore:"error,omitempty"`
 }
 
@@ -92,6 +98,21 @@ func InitStatus(c *StatusConfig) Status {
 	} else {
 		s.Disabled = s.Fail
 	}
+	if x.TooManyRequests != nil {
+		s.TooManyRequests = *x.TooManyRequests
+	} else {
+		s.TooManyRequests = s.Fail
+	}
+	if x.PasscodeInvalid != nil {
+		s.PasscodeInvalid = *x.PasscodeInvalid
+	} else {
+		s.PasscodeInvalid = s.Fail
+	}
+	if x.PasscodeExhausted != nil {
+		s.PasscodeExhausted = *x.PasscodeExhausted
+	} else {
+		s.PasscodeExhausted = s.PasscodeInvalid
+	}
 	if x.Error != nil {
 		s.Error = *x.Error
 	} else {
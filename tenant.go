@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	TenantKey = "tenant"
+
+	TenantBySubdomain = "subdomain"
+	TenantByHeader    = "header"
+	TenantByPath      = "path"
+)
+
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+func GetTenant(ctx context.Context) string {
+	return FromContext(ctx, TenantKey)
+}
+
+type TenantResolver struct {
+	Config TenantConfig
+}
+
+func NewTenantResolver(conf TenantConfig) *TenantResolver {
+	if len(conf.Resolver) == 0 {
+		conf.Resolver = TenantByHeader
+	}
+	if len(conf.Header) == 0 {
+		conf.Header = "X-Tenant-Id"
+	}
+	return &TenantResolver{Config: conf}
+}
+
+func (t *TenantResolver) Resolve(r *http.Request) string {
+	var tenant string
+	switch t.Config.Resolver {
+	case TenantBySubdomain:
+		host := r.Host
+		if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+			host = host[:i]
+		}
+		if len(t.Config.Domain) > 0 {
+			if !strings.HasSuffix(host, "."+t.Config.Domain) {
+				return ""
+			}
+			tenant = strings.TrimSuffix(host, "."+t.Config.Domain)
+		} else if i := strings.Index(host, "."); i > 0 {
+			tenant = host[:i]
+		}
+	case TenantByPath:
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if t.Config.PathIndex < len(segments) {
+			tenant = segments[t.Config.PathIndex]
+		}
+	default:
+		tenant = strings.TrimSpace(r.Header.Get(t.Config.Header))
+	}
+	tenant = strings.ToLower(tenant)
+	if len(tenant) == 0 || strings.Contains(tenant, ".") {
+		return ""
+	}
+	if len(t.Config.Tenants) > 0 {
+		for _, allowed := range t.Config.Tenants {
+			if allowed == tenant {
+				return tenant
+			}
+		}
+		return ""
+	}
+	return tenant
+}
+
+func (t *TenantResolver) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := t.Resolve(r)
+		if len(tenant) == 0 {
+			http.Error(w, "invalid tenant", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+	})
+}
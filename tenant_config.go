@@ -0,0 +1,27 @@
+package auth
+
+import "context"
+
+type TenantConfig struct {
+	Resolver  string   `yaml:"resolver" mapstructure:"resolver" json:"resolver,omitempty" gorm:"column:resolver" bson:"resolver,omitempty" dynamodbav:"resolver,omitempty" firestore:"resolver,omitempty"`
+	Header    string   `yaml:"header" mapstructure:"header" json:"header,omitempty" gorm:"column:header" bson:"header,omitempty" dynamodbav:"header,omitempty" firestore:"header,omitempty"`
+	Domain    string   `yaml:"domain" mapstructure:"domain" json:"domain,omitempty" gorm:"column:domain" bson:"domain,omitempty" dynamodbav:"domain,omitempty" firestore:"domain,omitempty"`
+	PathIndex int      `yaml:"path_index" mapstructure:"path_index" json:"pathIndex,omitempty" gorm:"column:pathindex" bson:"pathIndex,omitempty" dynamodbav:"pathIndex,omitempty" firestore:"pathIndex,omitempty"`
+	Tenants   []string `yaml:"tenants" mapstructure:"tenants" json:"tenants,omitempty" gorm:"column:tenants" bson:"tenants,omitempty" dynamodbav:"tenants,omitempty" firestore:"tenants,omitempty"`
+}
+
+type TenantAuthConfig struct {
+	Expires           int64          `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
+	MaxPasswordFailed int            `yaml:"max_password_failed" mapstructure:"max_password_failed" json:"maxPasswordFailed,omitempty" gorm:"column:maxpasswordfailed" bson:"maxPasswordFailed,omitempty" dynamodbav:"maxPasswordFailed,omitempty" firestore:"maxPasswordFailed,omitempty"`
+	LockedMinutes     int            `yaml:"locked_minutes" mapstructure:"locked_minutes" json:"lockedMinutes,omitempty" gorm:"column:lockedminutes" bson:"lockedMinutes,omitempty" dynamodbav:"lockedMinutes,omitempty" firestore:"lockedMinutes,omitempty"`
+	MaxPasswordAge    int32          `yaml:"max_password_age" mapstructure:"max_password_age" json:"maxPasswordAge,omitempty" gorm:"column:maxpasswordage" bson:"maxPasswordAge,omitempty" dynamodbav:"maxPasswordAge,omitempty" firestore:"maxPasswordAge,omitempty"`
+	Lockout           *LockoutConfig `yaml:"lockout" mapstructure:"lockout" json:"lockout,omitempty" gorm:"column:lockout" bson:"lockout,omitempty" dynamodbav:"lockout,omitempty" firestore:"lockout,omitempty"`
+}
+
+// TenantExpires returns the token expiry configured for the tenant in ctx, or expires when the tenant does not override it.
+func TenantExpires(ctx context.Context, tenants map[string]TenantAuthConfig, expires int64) int64 {
+	if t, ok := tenants[GetTenant(ctx)]; ok && t.Expires > 0 {
+		return t.Expires
+	}
+	return expires
+}
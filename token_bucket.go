@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TokenBucket struct {
+	Store    RateLimitStore
+	Capacity int
+	Window   time.Duration
+	locks    keyLocks
+}
+
+func NewTokenBucket(store RateLimitStore, capacity int, window time.Duration) *TokenBucket {
+	return &TokenBucket{Store: store, Capacity: capacity, Window: window}
+}
+
+func (b *TokenBucket) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	if b.Capacity <= 0 || b.Window <= 0 {
+		return true, 0, nil
+	}
+	unlock := b.locks.lock(key)
+	defer unlock()
+	now := time.Now()
+	state, err := b.Store.Get(ctx, key)
+	if err != nil {
+		return false, 0, err
+	}
+	tokens := float64(b.Capacity)
+	if parts := strings.Split(state, "|"); len(parts) == 2 {
+		t, er1 := strconv.ParseFloat(parts[0], 64)
+		last, er2 := strconv.ParseInt(parts[1], 10, 64)
+		if er1 == nil && er2 == nil {
+			elapsed := now.Sub(time.Unix(0, last))
+			tokens = t + elapsed.Seconds()*float64(b.Capacity)/b.Window.Seconds()
+			if tokens > float64(b.Capacity) {
+				tokens = float64(b.Capacity)
+			}
+		}
+	}
+	allowed := tokens >= 1
+	var retryAfter time.Duration
+	if allowed {
+		tokens = tokens - 1
+	} else {
+		retryAfter = time.Duration((1 - tokens) * float64(b.Window) / float64(b.Capacity))
+	}
+	value := strconv.FormatFloat(tokens, 'f', -1, 64) + "|" + strconv.FormatInt(now.UnixNano(), 10)
+	if err = b.Store.Put(ctx, key, value, b.Window); err != nil {
+		return false, 0, err
+	}
+	return allowed, retryAfter, nil
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/core-go/authentication/random"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TOTPService struct {
+	Repository TOTPRepository
+	Issuer     string
+	Digits     int
+	Period     int64
+	Skew       int
+	SecretSize int
+}
+
+func NewTOTPService(repository TOTPRepository, issuer string, options ...int) *TOTPService {
+	if repository == nil {
+		panic(errors.New("totp repository cannot be nil"))
+	}
+	digits := 6
+	period := 30
+	skew := 1
+	secretSize := 20
+	if len(options) > 0 && options[0] > 0 {
+		digits = options[0]
+	}
+	if len(options) > 1 && options[1] > 0 {
+		period = options[1]
+	}
+	if len(options) > 2 && options[2] >= 0 {
+		skew = options[2]
+	}
+	if len(options) > 3 && options[3] > 0 {
+		secretSize = options[3]
+	}
+	return &TOTPService{Repository: repository, Issuer: issuer, Digits: digits, Period: int64(period), Skew: skew, SecretSize: secretSize}
+}
+
+func (s *TOTPService) NewSecret(account string) (string, string, error) {
+	secret, err := GenerateTOTPSecret(s.SecretSize)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, BuildTOTPURI(s.Issuer, account, secret, s.Digits, s.Period), nil
+}
+
+func (s *TOTPService) Enroll(ctx context.Context, id string, secret string, passcode string) (bool, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := s.match(key, passcode, -1)
+	if !ok {
+		return false, nil
+	}
+	_, err = s.Repository.Save(ctx, id, secret)
+	if err != nil {
+		return false, err
+	}
+	_, err = s.Repository.Use(ctx, id, step)
+	return err == nil, err
+}
+
+func (s *TOTPService) Enrolled(ctx context.Context, id string) (bool, error) {
+	secret, _, err := s.Repository.Load(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return len(secret) > 0, nil
+}
+
+func (s *TOTPService) Verify(ctx context.Context, id string, passcode string) (bool, error) {
+	secret, lastStep, err := s.Repository.Load(ctx, id)
+	if err != nil || len(secret) == 0 {
+		return false, err
+	}
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := s.match(key, passcode, lastStep)
+	if !ok {
+		return false, nil
+	}
+	count, err := s.Repository.Use(ctx, id, step)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *TOTPService) match(key []byte, passcode string, lastStep int64) (int64, bool) {
+	if len(passcode) != s.Digits {
+		return 0, false
+	}
+	current := time.Now().Unix() / s.Period
+	for i := -s.Skew; i <= s.Skew; i++ {
+		step := current + int64(i)
+		if step <= lastStep {
+			continue
+		}
+		code := HOTP(key, step, s.Digits)
+		if subtle.ConstantTimeCompare([]byte(code), []byte(passcode)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func HOTP(key []byte, counter int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	h := hmac.New(sha1.New, key)
+	h.Write(msg[:])
+	sum := h.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := int64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+	mod := int64(1)
+	for i := 0; i < digits; i++ {
+		mod = mod * 10
+	}
+	return padLeft(strconv.FormatInt(value%mod, 10), digits, "0")
+}
+
+func GenerateTOTPSecret(size int) (string, error) {
+	b, err := random.Bytes(size)
+	if err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+func BuildTOTPURI(issuer string, account string, secret string, digits int, period int64) string {
+	label := url.PathEscape(account)
+	if len(issuer) > 0 {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+	q := url.Values{}
+	q.Set("secret", secret)
+	if len(issuer) > 0 {
+		q.Set("issuer", issuer)
+	}
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", strconv.Itoa(digits))
+	q.Set("period", strconv.FormatInt(period, 10))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, q.Encode())
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.ReplaceAll(strings.TrimRight(secret, "="), " ", ""))
+	return totpEncoding.DecodeString(s)
+}
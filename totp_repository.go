@@ -0,0 +1,9 @@
+package auth
+
+import "context"
+
+type TOTPRepository interface {
+	Load(ctx context.Context, id string) (string, int64, error)
+	Save(ctx context.Context, id string, secret string) (int64, error)
+	Use(ctx context.Context, id string, step int64) (int64, error)
+}
@@ -0,0 +1,370 @@
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	auth "github.com/core-go/authentication"
+	"github.com/core-go/authentication/random"
+)
+
+const (
+	registerPrefix = "webauthn:register:"
+	loginPrefix    = "webauthn:login:"
+	publicKey      = "public-key"
+	required       = "required"
+)
+
+var ErrVerification = errors.New("webauthn verification failed")
+
+var encoding = base64.RawURLEncoding
+
+type Service interface {
+	BeginRegistration(ctx context.Context, userId string, username string, displayName string) (*CredentialCreationOptions, error)
+	FinishRegistration(ctx context.Context, userId string, credential RegistrationCredential) (*auth.WebAuthnCredential, error)
+	BeginLogin(ctx context.Context, username string) (*CredentialRequestOptions, error)
+	FinishLogin(ctx context.Context, username string, credential AssertionCredential) (auth.AuthResult, error)
+}
+
+type Authenticator struct {
+	Config      Config
+	Status      auth.Status
+	Repository  auth.UserRepository
+	Credentials auth.CredentialRepository
+	Challenges  auth.CodeRepository
+	Privileges  func(ctx context.Context, id string) ([]auth.Privilege, error)
+}
+
+func NewAuthenticator(config Config, status auth.Status, repository auth.UserRepository, credentials auth.CredentialRepository, challenges auth.CodeRepository, options ...func(context.Context, string) ([]auth.Privilege, error)) *Authenticator {
+	if repository == nil || credentials == nil || challenges == nil {
+		panic(errors.New("user repository, credential repository and challenge repository cannot be nil"))
+	}
+	if len(config.RPId) == 0 || len(config.Origins) == 0 {
+		panic(errors.New("relying party id and origins are required"))
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 60000
+	}
+	var loadPrivileges func(context.Context, string) ([]auth.Privilege, error)
+	if len(options) > 0 {
+		loadPrivileges = options[0]
+	}
+	return &Authenticator{Config: config, Status: status, Repository: repository, Credentials: credentials, Challenges: challenges, Privileges: loadPrivileges}
+}
+
+func (s *Authenticator) BeginRegistration(ctx context.Context, userId string, username string, displayName string) (*CredentialCreationOptions, error) {
+	if len(userId) == 0 {
+		return nil, fmt.Errorf("%w: user id is required", ErrVerification)
+	}
+	challenge, err := s.newChallenge(ctx, registerPrefix+userId)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := s.Credentials.Load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if len(displayName) == 0 {
+		displayName = username
+	}
+	options := &CredentialCreationOptions{
+		Challenge: challenge,
+		Rp:        RelyingParty{Id: s.Config.RPId, Name: s.Config.RPName},
+		User:      User{Id: encoding.EncodeToString([]byte(userId)), Name: username, DisplayName: displayName},
+		PubKeyCredParams: []CredentialParameter{
+			{Type: publicKey, Alg: AlgES256},
+			{Type: publicKey, Alg: AlgEdDSA},
+			{Type: publicKey, Alg: AlgRS256},
+		},
+		Timeout:            s.Config.Timeout,
+		ExcludeCredentials: toDescriptors(credentials),
+		AuthenticatorSelection: &AuthenticatorSelection{
+			ResidentKey:      s.Config.ResidentKey,
+			UserVerification: s.Config.UserVerification,
+		},
+		Attestation: "none",
+	}
+	return options, nil
+}
+
+func (s *Authenticator) FinishRegistration(ctx context.Context, userId string, credential RegistrationCredential) (*auth.WebAuthnCredential, error) {
+	if credential.Type != publicKey {
+		return nil, fmt.Errorf("%w: invalid credential type", ErrVerification)
+	}
+	rawClientData, err := encoding.DecodeString(credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid client data", ErrVerification)
+	}
+	if err = s.verifyClientData(ctx, rawClientData, "webauthn.create", registerPrefix+userId); err != nil {
+		return nil, err
+	}
+	rawAttestation, err := encoding.DecodeString(credential.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid attestation object", ErrVerification)
+	}
+	obj, _, err := decodeCBOR(rawAttestation)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerification, err.Error())
+	}
+	attestation, ok := obj.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid attestation object", ErrVerification)
+	}
+	rawAuthData, ok := getBytes(attestation, "authData")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing authenticator data", ErrVerification)
+	}
+	data, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerification, err.Error())
+	}
+	if err = s.verifyAuthenticatorData(data); err != nil {
+		return nil, err
+	}
+	if len(data.CredentialId) == 0 || len(data.PublicKey) == 0 {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrVerification)
+	}
+	_, alg, err := parsePublicKey(data.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerification, err.Error())
+	}
+	id := encoding.EncodeToString(data.CredentialId)
+	existing, err := s.Credentials.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: credential is already registered", ErrVerification)
+	}
+	now := time.Now()
+	c := auth.WebAuthnCredential{
+		Id:          id,
+		UserId:      userId,
+		PublicKey:   data.PublicKey,
+		Algorithm:   alg,
+		SignCount:   int64(data.SignCount),
+		AAGUID:      data.AAGUID,
+		CreatedTime: &now,
+	}
+	if len(credential.Response.Transports) > 0 {
+		transports := strings.Join(credential.Response.Transports, ",")
+		c.Transports = &transports
+	}
+	if len(credential.Name) > 0 {
+		name := credential.Name
+		c.Name = &name
+	}
+	if _, err = s.Credentials.Save(ctx, c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *Authenticator) BeginLogin(ctx context.Context, username string) (*CredentialRequestOptions, error) {
+	options := &CredentialRequestOptions{RpId: s.Config.RPId, Timeout: s.Config.Timeout, UserVerification: s.Config.UserVerification}
+	user, err := s.Repository.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := s.newChallenge(ctx, loginPrefix+username)
+	if err != nil {
+		return nil, err
+	}
+	options.Challenge = challenge
+	if user != nil {
+		credentials, er1 := s.Credentials.Load(ctx, user.Id)
+		if er1 != nil {
+			return nil, er1
+		}
+		options.AllowCredentials = toDescriptors(credentials)
+	}
+	return options, nil
+}
+
+func (s *Authenticator) FinishLogin(ctx context.Context, username string, credential AssertionCredential) (auth.AuthResult, error) {
+	result := auth.AuthResult{Status: s.Status.Fail}
+	if credential.Type != publicKey || len(credential.Id) == 0 {
+		return result, nil
+	}
+	user, er1 := s.Repository.GetUser(ctx, username)
+	if er1 != nil {
+		return result, er1
+	}
+	if user == nil {
+		result.Status = s.Status.NotFound
+		return result, nil
+	}
+	c, er2 := s.Credentials.Get(ctx, credential.Id)
+	if er2 != nil {
+		return result, er2
+	}
+	if c == nil || c.UserId != user.Id {
+		return result, nil
+	}
+	if len(credential.Response.UserHandle) > 0 {
+		handle, err := encoding.DecodeString(credential.Response.UserHandle)
+		if err != nil || string(handle) != user.Id {
+			return result, nil
+		}
+	}
+	rawClientData, err := encoding.DecodeString(credential.Response.ClientDataJSON)
+	if err != nil {
+		return result, nil
+	}
+	if err = s.verifyClientData(ctx, rawClientData, "webauthn.get", loginPrefix+username); err != nil {
+		if errors.Is(err, ErrVerification) {
+			return result, nil
+		}
+		return result, err
+	}
+	rawAuthData, err := encoding.DecodeString(credential.Response.AuthenticatorData)
+	if err != nil {
+		return result, nil
+	}
+	data, err := parseAuthenticatorData(rawAuthData)
+	if err != nil || s.verifyAuthenticatorData(data) != nil {
+		return result, nil
+	}
+	signature, err := encoding.DecodeString(credential.Response.Signature)
+	if err != nil {
+		return result, nil
+	}
+	key, _, err := parsePublicKey(c.PublicKey)
+	if err != nil {
+		return result, err
+	}
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if !verifySignature(key, signed, signature) {
+		return result, nil
+	}
+	signCount := int64(data.SignCount)
+	if (signCount > 0 || c.SignCount > 0) && signCount <= c.SignCount {
+		return result, nil
+	}
+	if _, err = s.Credentials.Update(ctx, c.Id, signCount, time.Now()); err != nil {
+		return result, err
+	}
+
+	if user.Disable || !auth.IsAccessDateValid(user.AccessDateFrom, user.AccessDateTo) {
+		result.Status = s.Status.Disabled
+		return result, nil
+	}
+	if user.Suspended {
+		result.Status = s.Status.Suspended
+		return result, nil
+	}
+	if user.LockedUntilTime != nil && time.Now().Before(*user.LockedUntilTime) {
+		result.Status = s.Status.Locked
+		return result, nil
+	}
+	if !auth.IsAccessTimeValid(user.AccessTimeFrom, user.AccessTimeTo) {
+		result.Status = s.Status.AccessTimeLocked
+		return result, nil
+	}
+	if user.Deactivated != nil && *user.Deactivated {
+		result.Status = s.Status.SuccessAndReactivated
+	} else {
+		result.Status = s.Status.Success
+	}
+	account := auth.ToUserAccount(*user)
+	if s.Privileges != nil {
+		privileges, er3 := s.Privileges(ctx, user.Id)
+		if er3 != nil {
+			return result, er3
+		}
+		if len(privileges) > 0 {
+			account.Privileges = privileges
+		}
+	}
+	result.User = &account
+	if er4 := s.Repository.Pass(ctx, user.Id, user.Deactivated); er4 != nil {
+		return result, er4
+	}
+	return result, nil
+}
+
+func (s *Authenticator) newChallenge(ctx context.Context, key string) (string, error) {
+	challenge, err := random.Token(32)
+	if err != nil {
+		return "", err
+	}
+	expiredAt := time.Now().Add(time.Duration(s.Config.Timeout) * time.Millisecond)
+	if _, err := s.Challenges.Save(ctx, key, challenge, expiredAt); err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+func (s *Authenticator) verifyClientData(ctx context.Context, raw []byte, ceremony string, key string) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("%w: invalid client data", ErrVerification)
+	}
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: invalid ceremony type", ErrVerification)
+	}
+	if !contains(s.Config.Origins, data.Origin) {
+		return fmt.Errorf("%w: origin is not allowed", ErrVerification)
+	}
+	challenge, expiredAt, err := s.Challenges.Load(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(challenge) == 0 {
+		return fmt.Errorf("%w: challenge not found", ErrVerification)
+	}
+	if _, err = s.Challenges.Delete(ctx, key); err != nil {
+		return err
+	}
+	if time.Now().After(expiredAt) {
+		return fmt.Errorf("%w: challenge is expired", ErrVerification)
+	}
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(data.Challenge)) != 1 {
+		return fmt.Errorf("%w: challenge does not match", ErrVerification)
+	}
+	return nil
+}
+
+func (s *Authenticator) verifyAuthenticatorData(data *authenticatorData) error {
+	rpIdHash := sha256.Sum256([]byte(s.Config.RPId))
+	if !bytes.Equal(data.RPIdHash, rpIdHash[:]) {
+		return fmt.Errorf("%w: relying party id does not match", ErrVerification)
+	}
+	if data.Flags&flagUserPresent == 0 {
+		return fmt.Errorf("%w: user is not present", ErrVerification)
+	}
+	if s.Config.UserVerification == required && data.Flags&flagUserVerified == 0 {
+		return fmt.Errorf("%w: user is not verified", ErrVerification)
+	}
+	return nil
+}
+
+func toDescriptors(credentials []auth.WebAuthnCredential) []CredentialDescriptor {
+	descriptors := make([]CredentialDescriptor, 0)
+	for _, c := range credentials {
+		d := CredentialDescriptor{Type: publicKey, Id: c.Id}
+		if c.Transports != nil && len(*c.Transports) > 0 {
+			d.Transports = strings.Split(*c.Transports, ",")
+		}
+		descriptors = append(descriptors, d)
+	}
+	return descriptors
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+type authenticatorData struct {
+	RPIdHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       string
+	CredentialId []byte
+	PublicKey    []byte
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+	d := &authenticatorData{RPIdHash: data[:32], Flags: data[32], SignCount: binary.BigEndian.Uint32(data[33:37])}
+	if d.Flags&flagAttested == 0 {
+		return d, nil
+	}
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, errors.New("attested credential data is too short")
+	}
+	d.AAGUID = hex.EncodeToString(rest[:16])
+	n := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < n {
+		return nil, errors.New("invalid credential id length")
+	}
+	d.CredentialId = rest[:n]
+	rest = rest[n:]
+	_, remain, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, err
+	}
+	d.PublicKey = rest[:len(rest)-len(remain)]
+	return d, nil
+}
@@ -0,0 +1,10 @@
+package webauthn
+
+type Config struct {
+	RPId             string   `yaml:"rp_id" mapstructure:"rp_id" json:"rpId,omitempty" gorm:"column:rpid" bson:"rpId,omitempty" dynamodbav:"rpId,omitempty" firestore:"rpId,omitempty"`
+	RPName           string   `yaml:"rp_name" mapstructure:"rp_name" json:"rpName,omitempty" gorm:"column:rpname" bson:"rpName,omitempty" dynamodbav:"rpName,omitempty" firestore:"rpName,omitempty"`
+	Origins          []string `yaml:"origins" mapstructure:"origins" json:"origins,omitempty" gorm:"column:origins" bson:"origins,omitempty" dynamodbav:"origins,omitempty" firestore:"origins,omitempty"`
+	Timeout          int64    `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+	UserVerification string   `yaml:"user_verification" mapstructure:"user_verification" json:"userVerification,omitempty" gorm:"column:userverification" bson:"userVerification,omitempty" dynamodbav:"userVerification,omitempty" firestore:"userVerification,omitempty"`
+	ResidentKey      string   `yaml:"resident_key" mapstructure:"resident_key" json:"residentKey,omitempty" gorm:"column:residentkey" bson:"residentKey,omitempty" dynamodbav:"residentKey,omitempty" firestore:"residentKey,omitempty"`
+}
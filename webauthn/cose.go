@@ -0,0 +1,84 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+const (
+	keyTypeOKP = 1
+	keyTypeEC2 = 2
+	keyTypeRSA = 3
+	curveP256  = 1
+	curveEd    = 6
+)
+
+var errUnsupportedKey = errors.New("unsupported public key")
+
+func parsePublicKey(data []byte) (crypto.PublicKey, int64, error) {
+	v, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, 0, errUnsupportedKey
+	}
+	kty, _ := getInt(m, int64(1))
+	alg, _ := getInt(m, int64(3))
+	switch kty {
+	case keyTypeEC2:
+		crv, _ := getInt(m, int64(-1))
+		x, ok1 := getBytes(m, int64(-2))
+		y, ok2 := getBytes(m, int64(-3))
+		if crv != curveP256 || alg != AlgES256 || !ok1 || !ok2 {
+			return nil, 0, errUnsupportedKey
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, 0, errUnsupportedKey
+		}
+		return key, alg, nil
+	case keyTypeRSA:
+		n, ok1 := getBytes(m, int64(-1))
+		e, ok2 := getBytes(m, int64(-2))
+		if alg != AlgRS256 || !ok1 || !ok2 || len(e) > 4 {
+			return nil, 0, errUnsupportedKey
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, alg, nil
+	case keyTypeOKP:
+		crv, _ := getInt(m, int64(-1))
+		x, ok1 := getBytes(m, int64(-2))
+		if crv != curveEd || alg != AlgEdDSA || !ok1 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, errUnsupportedKey
+		}
+		return ed25519.PublicKey(x), alg, nil
+	}
+	return nil, 0, errUnsupportedKey
+}
+
+func verifySignature(key crypto.PublicKey, data []byte, signature []byte) bool {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		h := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(k, h[:], signature)
+	case *rsa.PublicKey:
+		h := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, data, signature)
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "time"
+
+type WebAuthnCredential struct {
+	Id           string     `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	UserId       string     `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:user_id" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	PublicKey    []byte     `yaml:"public_key" mapstructure:"public_key" json:"publicKey,omitempty" gorm:"column:public_key" bson:"publicKey,omitempty" dynamodbav:"publicKey,omitempty" firestore:"publicKey,omitempty"`
+	Algorithm    int64      `yaml:"algorithm" mapstructure:"algorithm" json:"algorithm,omitempty" gorm:"column:algorithm" bson:"algorithm,omitempty" dynamodbav:"algorithm,omitempty" firestore:"algorithm,omitempty"`
+	SignCount    int64      `yaml:"sign_count" mapstructure:"sign_count" json:"signCount,omitempty" gorm:"column:sign_count" bson:"signCount,omitempty" dynamodbav:"signCount,omitempty" firestore:"signCount,omitempty"`
+	AAGUID       string     `yaml:"aaguid" mapstructure:"aaguid" json:"aaguid,omitempty" gorm:"column:aaguid" bson:"aaguid,omitempty" dynamodbav:"aaguid,omitempty" firestore:"aaguid,omitempty"`
+	Transports   *string    `yaml:"transports" mapstructure:"transports" json:"transports,omitempty" gorm:"column:transports" bson:"transports,omitempty" dynamodbav:"transports,omitempty" firestore:"transports,omitempty"`
+	Name         *string    `yaml:"name" mapstructure:"name" json:"name,omitempty" gorm:"column:name" bson:"name,omitempty" dynamodbav:"name,omitempty" firestore:"name,omitempty"`
+	CreatedTime  *time.Time `yaml:"created_time" mapstructure:"created_time" json:"createdTime,omitempty" gorm:"column:created_time" bson:"createdTime,omitempty" dynamodbav:"createdTime,omitempty" firestore:"createdTime,omitempty"`
+	LastUsedTime *time.Time `yaml:"last_used_time" mapstructure:"last_used_time" json:"lastUsedTime,omitempty" gorm:"column:last_used_time" bson:"lastUsedTime,omitempty" dynamodbav:"lastUsedTime,omitempty" firestore:"lastUsedTime,omitempty"`
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type WebhookMessage struct {
+	Channel  string    `json:"channel,omitempty"`
+	To       string    `json:"to,omitempty"`
+	Subject  string    `json:"subject,omitempty"`
+	Body     string    `json:"body,omitempty"`
+	Code     string    `json:"code,omitempty"`
+	ExpireAt time.Time `json:"expireAt,omitempty"`
+	Language string    `json:"language,omitempty"`
+}
+
+type WebhookSender struct {
+	Url       string
+	Client    *http.Client
+	Headers   map[string]string
+	Templates *CodeTemplates
+}
+
+func NewWebhookSender(url string, templates *CodeTemplates, options ...*http.Client) *WebhookSender {
+	if len(url) == 0 {
+		panic(errors.New("webhook url cannot be empty"))
+	}
+	var client *http.Client
+	if len(options) > 0 && options[0] != nil {
+		client = options[0]
+	} else {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookSender{Url: url, Client: client, Headers: make(map[string]string), Templates: templates}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, msg CodeMessage) error {
+	m := WebhookMessage{Channel: msg.Channel, To: msg.To, Body: msg.Code, Code: msg.Code, ExpireAt: msg.ExpireAt, Language: msg.Language}
+	if s.Templates != nil {
+		subject, body, er0 := s.Templates.Render(msg)
+		if er0 != nil {
+			return er0
+		}
+		m.Subject = subject
+		m.Body = body
+	}
+	data, er1 := json.Marshal(m)
+	if er1 != nil {
+		return er1
+	}
+	req, er2 := http.NewRequestWithContext(ctx, http.MethodPost, s.Url, bytes.NewReader(data))
+	if er2 != nil {
+		return er2
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	res, er3 := s.Client.Do(req)
+	if er3 != nil {
+		return er3
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", s.Url, res.StatusCode)
+	}
+	return nil
+}